@@ -0,0 +1,456 @@
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	openai2 "github.com/acorn-io/assistant-runtime/pkg/openai"
+	"github.com/sashabaranov/go-openai"
+)
+
+type Backend struct {
+	config Config
+}
+
+func NewBackend(cfg Config) *Backend {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultURL
+	}
+	return &Backend{
+		config: cfg,
+	}
+}
+
+type request struct {
+	Model     string    `json:"model"`
+	System    string    `json:"system,omitempty"`
+	Messages  []message `json:"messages"`
+	MaxTokens int       `json:"max_tokens"`
+	Stream    bool      `json:"stream"`
+	Tools     []tool    `json:"tools,omitempty"`
+}
+
+type message struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+type contentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	Source    *imageSource    `json:"source,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+type imageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+type tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+type event struct {
+	Type         string        `json:"type"`
+	Index        int           `json:"index"`
+	Message      *eventMessage `json:"message,omitempty"`
+	ContentBlock *contentBlock `json:"content_block,omitempty"`
+	Delta        *delta        `json:"delta,omitempty"`
+	Usage        *usage        `json:"usage,omitempty"`
+	Error        *apiError     `json:"error,omitempty"`
+}
+
+type eventMessage struct {
+	ID    string `json:"id"`
+	Model string `json:"model"`
+	Usage *usage `json:"usage,omitempty"`
+}
+
+type usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type delta struct {
+	Type        string `json:"type"`
+	Text        string `json:"text,omitempty"`
+	PartialJSON string `json:"partial_json,omitempty"`
+	StopReason  string `json:"stop_reason,omitempty"`
+}
+
+type apiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Error *apiError `json:"error,omitempty"`
+}
+
+func (b *Backend) CreateChatCompletionStream(ctx context.Context, request openai.ChatCompletionRequest) (openai2.Stream, error) {
+	body, err := json.Marshal(toRequest(request))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(b.config.BaseURL, "/")+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("X-API-Key", b.config.APIKey)
+	req.Header.Set("Anthropic-Version", APIVersion)
+	for k, v := range b.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, openai2.WithRetryAfter(toError(resp), openai2.ParseRetryAfter(resp.Header))
+	}
+
+	return &stream{
+		body:      resp.Body,
+		reader:    bufio.NewReader(resp.Body),
+		toolIndex: map[int]int{},
+	}, nil
+}
+
+func toError(resp *http.Response) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var errResp errorResponse
+	if err := json.Unmarshal(data, &errResp); err != nil || errResp.Error == nil {
+		return &openai.RequestError{
+			HTTPStatusCode: resp.StatusCode,
+			Err:            fmt.Errorf("%s", data),
+		}
+	}
+	return &openai.APIError{
+		Type:           errResp.Error.Type,
+		Message:        errResp.Error.Message,
+		HTTPStatusCode: resp.StatusCode,
+	}
+}
+
+func toRequest(in openai.ChatCompletionRequest) (out request) {
+	out.Model = in.Model
+	out.MaxTokens = in.MaxTokens
+
+	for _, t := range in.Tools {
+		schema := t.Function.Parameters
+		if schema == nil {
+			schema = map[string]any{
+				"type": "object",
+			}
+		}
+		out.Tools = append(out.Tools, tool{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			InputSchema: schema,
+		})
+	}
+
+	var systems []string
+	for _, msg := range in.Messages {
+		var (
+			role   = msg.Role
+			blocks []contentBlock
+		)
+
+		switch msg.Role {
+		case openai.ChatMessageRoleSystem:
+			systems = append(systems, msg.Content)
+			continue
+		case openai.ChatMessageRoleTool:
+			role = openai.ChatMessageRoleUser
+			blocks = append(blocks, contentBlock{
+				Type:      "tool_result",
+				ToolUseID: msg.ToolCallID,
+				Content:   toText(msg),
+			})
+		default:
+			blocks = append(blocks, toBlocks(msg)...)
+			for _, call := range msg.ToolCalls {
+				input := json.RawMessage(call.Function.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, contentBlock{
+					Type:  "tool_use",
+					ID:    call.ID,
+					Name:  call.Function.Name,
+					Input: input,
+				})
+			}
+		}
+
+		if len(blocks) == 0 {
+			continue
+		}
+
+		// Anthropic requires roles to alternate, so consecutive messages of the same role, such as multiple
+		// tool results, are merged into one message.
+		if len(out.Messages) > 0 && out.Messages[len(out.Messages)-1].Role == role {
+			out.Messages[len(out.Messages)-1].Content = append(out.Messages[len(out.Messages)-1].Content, blocks...)
+		} else {
+			out.Messages = append(out.Messages, message{
+				Role:    role,
+				Content: blocks,
+			})
+		}
+	}
+
+	if instructions := openai2.SchemaInstructions(in.ResponseFormat); instructions != "" {
+		systems = append(systems, instructions)
+	}
+
+	out.System = strings.Join(systems, "\n\n")
+	return
+}
+
+func toText(msg openai.ChatCompletionMessage) string {
+	if len(msg.MultiContent) == 0 {
+		return msg.Content
+	}
+	var texts []string
+	for _, part := range msg.MultiContent {
+		if part.Type == openai.ChatMessagePartTypeText {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func toBlocks(msg openai.ChatCompletionMessage) (result []contentBlock) {
+	if len(msg.MultiContent) == 0 {
+		if msg.Content == "" {
+			return nil
+		}
+		return []contentBlock{
+			{
+				Type: "text",
+				Text: msg.Content,
+			},
+		}
+	}
+
+	for _, part := range msg.MultiContent {
+		switch part.Type {
+		case openai.ChatMessagePartTypeText:
+			if part.Text != "" {
+				result = append(result, contentBlock{
+					Type: "text",
+					Text: part.Text,
+				})
+			}
+		case openai.ChatMessagePartTypeImageURL:
+			if part.ImageURL == nil {
+				continue
+			}
+			if source, ok := toImageSource(part.ImageURL.URL); ok {
+				result = append(result, contentBlock{
+					Type:   "image",
+					Source: source,
+				})
+			} else {
+				result = append(result, contentBlock{
+					Type: "text",
+					Text: fmt.Sprintf("Image URL %s", part.ImageURL.URL),
+				})
+			}
+		}
+	}
+	return
+}
+
+func toImageSource(url string) (*imageSource, bool) {
+	data, ok := strings.CutPrefix(url, "data:")
+	if !ok {
+		return nil, false
+	}
+	mediaType, data, ok := strings.Cut(data, ";base64,")
+	if !ok {
+		return nil, false
+	}
+	return &imageSource{
+		Type:      "base64",
+		MediaType: mediaType,
+		Data:      data,
+	}, true
+}
+
+type stream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+	id     string
+	model  string
+	// parts is the number of content parts of the message so far, all the text is one part. Tool calls are
+	// numbered after the parts before them, as the index of their part.
+	parts     int
+	text      bool
+	toolIndex map[int]int
+	usage     usage
+	hasUsage  bool
+}
+
+func (s *stream) Close() {
+	_ = s.body.Close()
+}
+
+func (s *stream) Usage() (openai.Usage, bool) {
+	return openai.Usage{
+		PromptTokens:     s.usage.InputTokens,
+		CompletionTokens: s.usage.OutputTokens,
+		TotalTokens:      s.usage.InputTokens + s.usage.OutputTokens,
+	}, s.hasUsage
+}
+
+func (s *stream) chunk(delta openai.ChatCompletionStreamChoiceDelta) openai.ChatCompletionStreamResponse {
+	return openai.ChatCompletionStreamResponse{
+		ID:     s.id,
+		Object: "chat.completion.chunk",
+		Model:  s.model,
+		Choices: []openai.ChatCompletionStreamChoice{
+			{
+				Delta: delta,
+			},
+		},
+	}
+}
+
+func (s *stream) textChunk(text string) openai.ChatCompletionStreamResponse {
+	if !s.text {
+		s.text = true
+		s.parts++
+	}
+	return s.chunk(openai.ChatCompletionStreamChoiceDelta{
+		Content: text,
+	})
+}
+
+func (s *stream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	for {
+		line, err := s.reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(line) == 0 {
+			return openai.ChatCompletionStreamResponse{}, io.EOF
+		} else if err != nil && !errors.Is(err, io.EOF) {
+			return openai.ChatCompletionStreamResponse{}, err
+		}
+
+		data, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:"))
+		if !ok {
+			continue
+		}
+
+		var e event
+		if err := json.Unmarshal(bytes.TrimSpace(data), &e); err != nil {
+			return openai.ChatCompletionStreamResponse{}, err
+		}
+
+		switch e.Type {
+		case "message_start":
+			if e.Message != nil {
+				s.id = e.Message.ID
+				s.model = e.Message.Model
+				if e.Message.Usage != nil {
+					s.usage = *e.Message.Usage
+					s.hasUsage = true
+				}
+			}
+			return s.chunk(openai.ChatCompletionStreamChoiceDelta{
+				Role: openai.ChatMessageRoleAssistant,
+			}), nil
+		case "content_block_start":
+			if e.ContentBlock == nil {
+				continue
+			}
+			switch e.ContentBlock.Type {
+			case "tool_use":
+				idx := s.parts
+				s.parts++
+				s.toolIndex[e.Index] = idx
+				return s.chunk(openai.ChatCompletionStreamChoiceDelta{
+					ToolCalls: []openai.ToolCall{
+						{
+							Index: &idx,
+							ID:    e.ContentBlock.ID,
+							Type:  openai.ToolTypeFunction,
+							Function: openai.FunctionCall{
+								Name: e.ContentBlock.Name,
+							},
+						},
+					},
+				}), nil
+			case "text":
+				if e.ContentBlock.Text != "" {
+					return s.textChunk(e.ContentBlock.Text), nil
+				}
+			}
+		case "content_block_delta":
+			if e.Delta == nil {
+				continue
+			}
+			switch e.Delta.Type {
+			case "text_delta":
+				if e.Delta.Text != "" {
+					return s.textChunk(e.Delta.Text), nil
+				}
+			case "input_json_delta":
+				idx, ok := s.toolIndex[e.Index]
+				if !ok {
+					continue
+				}
+				return s.chunk(openai.ChatCompletionStreamChoiceDelta{
+					ToolCalls: []openai.ToolCall{
+						{
+							Index: &idx,
+							Function: openai.FunctionCall{
+								Arguments: e.Delta.PartialJSON,
+							},
+						},
+					},
+				}), nil
+			}
+		case "message_delta":
+			// The output tokens in message_delta are cumulative
+			if e.Usage != nil {
+				s.usage.OutputTokens = e.Usage.OutputTokens
+				s.hasUsage = true
+			}
+		case "message_stop":
+			return openai.ChatCompletionStreamResponse{}, io.EOF
+		case "error":
+			if e.Error != nil {
+				return openai.ChatCompletionStreamResponse{}, &openai.APIError{
+					Type:    e.Error.Type,
+					Message: e.Error.Message,
+				}
+			}
+			return openai.ChatCompletionStreamResponse{}, fmt.Errorf("unknown error in anthropic stream: %s", data)
+		}
+	}
+}
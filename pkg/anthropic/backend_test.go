@@ -0,0 +1,46 @@
+package anthropic
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStreamToolIndex(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_1","model":"claude-3-opus-20240229"}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me check."}}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":\"Paris\"}"}}`,
+		`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_2","name":"get_time"}}`,
+		`{"type":"message_stop"}`,
+	}
+	body := "data: " + strings.Join(events, "\n\ndata: ") + "\n\n"
+	s := &stream{
+		body:      io.NopCloser(strings.NewReader(body)),
+		reader:    bufio.NewReader(strings.NewReader(body)),
+		toolIndex: map[int]int{},
+	}
+
+	var indexes []int
+	for {
+		response, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		for _, call := range response.Choices[0].Delta.ToolCalls {
+			indexes = append(indexes, *call.Index)
+		}
+	}
+
+	// The text is the first content part, so the tool calls are the second and third
+	if want := []int{1, 1, 2}; !slices.Equal(indexes, want) {
+		t.Errorf("tool call indexes = %v, want %v", indexes, want)
+	}
+}
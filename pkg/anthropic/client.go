@@ -0,0 +1,41 @@
+package anthropic
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/acorn-io/assistant-runtime/pkg/openai"
+)
+
+const (
+	DefaultURL         = "https://api.anthropic.com"
+	DefaultModel       = "claude-3-opus-20240229"
+	DefaultVisionModel = DefaultModel
+	APIVersion         = "2023-06-01"
+)
+
+var (
+	key = os.Getenv("ANTHROPIC_API_KEY")
+	url = os.Getenv("ANTHROPIC_URL")
+)
+
+type Config struct {
+	BaseURL string
+	APIKey  string
+	Headers map[string]string
+}
+
+// Configured returns true if the environment has credentials for the Anthropic API
+func Configured() bool {
+	return key != ""
+}
+
+func NewClient() (*openai.Client, error) {
+	if key == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY env var is not set")
+	}
+	return openai.NewClientWithBackend(NewBackend(Config{
+		BaseURL: url,
+		APIKey:  key,
+	}), DefaultModel, DefaultVisionModel), nil
+}
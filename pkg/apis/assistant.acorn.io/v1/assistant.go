@@ -73,13 +73,37 @@ type AssistantSpec struct {
 	Name         string             `json:"name,omitempty"`
 	Description  string             `json:"description,omitempty"`
 	Instructions string             `json:"instructions,omitempty"`
+	Provider     string             `json:"provider,omitempty"`
 	Model        string             `json:"model,omitempty"`
 	Vision       bool               `json:"vision,omitempty"`
 	Tools        []Tool             `json:"tools,omitempty"`
 	Parameters   *jsonschema.Schema `json:"parameters"`
 	MaxTokens    int                `json:"maxTokens,omitempty"`
 	JSONResponse bool               `json:"jsonResponse,omitempty"`
-	Cache        *bool              `json:"cache,omitempty"`
+	// ResponseSchema is the schema responses must match. It implies JSONResponse.
+	ResponseSchema *jsonschema.Schema `json:"responseSchema,omitempty"`
+	Cache          *bool              `json:"cache,omitempty"`
+	Truncation     *TruncationPolicy  `json:"truncation,omitempty"`
+}
+
+type TruncationStrategy string
+
+const (
+	// TruncationStrategyDropOldest drops the oldest messages until the prompt fits the context window
+	TruncationStrategyDropOldest TruncationStrategy = "drop-oldest"
+	// TruncationStrategyKeepLast sends the instructions and only the last N messages
+	TruncationStrategyKeepLast TruncationStrategy = "keep-last"
+	// TruncationStrategySummarize replaces the messages that would be dropped with a summary of them
+	TruncationStrategySummarize TruncationStrategy = "summarize"
+)
+
+type TruncationPolicy struct {
+	// Strategy defaults to drop-oldest
+	Strategy TruncationStrategy `json:"strategy,omitempty"`
+	// LastMessages is the number of messages kept by the keep-last strategy
+	LastMessages int `json:"lastMessages,omitempty"`
+	// ContextWindow overrides the context window size, in tokens, of the model
+	ContextWindow int `json:"contextWindow,omitempty"`
 }
 
 type FunctionDefinition struct {
@@ -87,6 +111,9 @@ type FunctionDefinition struct {
 	Description string             `json:"description,omitempty"`
 	Domain      string             `json:"domain,omitempty"`
 	Parameters  *jsonschema.Schema `json:"parameters"`
+	// Client functions are called by the client of the thread instead of the runtime. The client sets
+	// spec.output of the InvokeTool to the result of the call.
+	Client bool `json:"client,omitempty"`
 }
 
 type Tool struct {
@@ -94,7 +121,18 @@ type Tool struct {
 	Function FunctionDefinition `json:"function,omitempty"`
 }
 
+// Function returns the definition of the function called name, or nil if the assistant has no such function
+func (in *Assistant) Function(name string) *FunctionDefinition {
+	for i, tool := range in.Spec.Tools {
+		if tool.Function.Name == name {
+			return &in.Spec.Tools[i].Function
+		}
+	}
+	return nil
+}
+
 type AssistantStatus struct {
+	Usage      *UsageTotals       `json:"usage,omitempty"`
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
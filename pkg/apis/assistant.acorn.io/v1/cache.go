@@ -2,13 +2,36 @@ package v1
 
 import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+// Labels set on a Cache to record which assistant and thread first generated the response
+const (
+	CacheAssistantLabel = "assistant.acorn.io/assistant-name"
+	CacheThreadLabel    = "assistant.acorn.io/thread-name"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type Cache struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Content []byte `json:"content,omitempty"`
+	Content []byte      `json:"content,omitempty"`
+	Usage   *Usage      `json:"usage,omitempty"`
+	Status  CacheStatus `json:"status,omitempty"`
+}
+
+type CacheStatus struct {
+	// Size is the number of bytes of content
+	Size     int64        `json:"size,omitempty"`
+	HitCount int          `json:"hitCount,omitempty"`
+	LastHit  *metav1.Time `json:"lastHit,omitempty"`
+}
+
+// LastUsed returns the time of the last hit, or when the cache was created if it has never been hit
+func (in *Cache) LastUsed() metav1.Time {
+	if in.Status.LastHit != nil && in.Status.LastHit.After(in.CreationTimestamp.Time) {
+		return *in.Status.LastHit
+	}
+	return in.CreationTimestamp
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
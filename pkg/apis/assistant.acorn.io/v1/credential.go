@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"github.com/acorn-io/baaah/pkg/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	_ conditions.Conditions = (*Credential)(nil)
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Credential holds secret values, such as the API keys of model providers, in the runtime store so that
+// tenants of the runtime API can manage their own keys
+type Credential struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CredentialSpec   `json:"spec,omitempty"`
+	Status CredentialStatus `json:"status,omitempty"`
+}
+
+func (in *Credential) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
+type CredentialSpec struct {
+	// Data are the secret values by key. The values are blank when read from the API, except by the controller,
+	// and a blank value in an update keeps the stored value.
+	Data map[string]string `json:"data,omitempty"`
+}
+
+type CredentialStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CredentialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Credential `json:"items"`
+}
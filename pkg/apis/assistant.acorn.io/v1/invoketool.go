@@ -9,6 +9,13 @@ var (
 	_ conditions.Conditions = (*InvokeTool)(nil)
 )
 
+// InvokeToolConditionArgumentsValid is false when the arguments of the tool call don't match the parameters of
+// the tool, in which case the tool is not called and the errors are sent back to the model.
+const InvokeToolConditionArgumentsValid = "ArgumentsValid"
+
+// InvokeToolConditionCancelled is true when the tool call was cancelled before it returned
+const InvokeToolConditionCancelled = "Cancelled"
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type InvokeTool struct {
@@ -28,9 +35,14 @@ type InvokeToolSpec struct {
 	ParentMessageName   string   `json:"parentMessageName,omitempty"`
 	ResponseMessageName string   `json:"responseMessageName,omitempty"`
 	ToolCall            ToolCall `json:"toolCall,omitempty"`
+	// Cancel is set when the message making the call is cancelled
+	Cancel bool `json:"cancel,omitempty"`
+	// Output is the result of a call to a client function, set by the client
+	Output *string `json:"output,omitempty"`
 }
 
 type InvokeToolStatus struct {
+	Phase                Phase              `json:"phase,omitempty"`
 	Content              []ContentPart      `json:"content,omitempty"`
 	AssistantMessageName string             `json:"assistantMessageName,omitempty"`
 	Generation           int64              `json:"generation,omitempty"`
@@ -2,9 +2,11 @@ package v1
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/acorn-io/baaah/pkg/conditions"
+	"github.com/acorn-io/baaah/pkg/name"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -21,6 +23,18 @@ const (
 
 type RoleType string
 
+// MessageConditionRetrying is true while a completion that failed with a transient error is waiting
+// for status.runAfter to be tried again.
+const MessageConditionRetrying = "Retrying"
+
+// MessageConditionResponseValid records whether a completion matched the assistant's response schema,
+// after at most one attempt to repair it.
+const MessageConditionResponseValid = "ResponseValid"
+
+// MessageConditionCancelled is true when generation was stopped by spec.cancel. Any content generated before
+// that is kept.
+const MessageConditionCancelled = "Cancelled"
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type Message struct {
@@ -39,10 +53,33 @@ func (in *Message) GetDescription() string {
 	return content
 }
 
+// ResponseName is the name of a response of the assistant to the message. The first response is alternative 0.
+func (in *Message) ResponseName(alternative int) string {
+	if alternative == 0 {
+		return name.SafeHashConcatName(in.Name, "resp")
+	}
+	return name.SafeHashConcatName(in.Name, "resp", strconv.Itoa(alternative))
+}
+
 func (in *Message) GetConditions() *[]metav1.Condition {
 	return &in.Status.Conditions
 }
 
+// MessageThreadNameField is the field messages are indexed by in the controller to list the messages of a thread
+const MessageThreadNameField = "status.threadName"
+
+// Has and Get let field selectors on the thread name match messages
+func (in *Message) Has(field string) bool {
+	return field == MessageThreadNameField
+}
+
+func (in *Message) Get(field string) string {
+	if field == MessageThreadNameField {
+		return in.Status.ThreadName
+	}
+	return ""
+}
+
 type MessageBody struct {
 	Role     RoleType      `json:"role,omitempty"`
 	Content  []ContentPart `json:"content,omitempty" column:"name=Message,jsonpath=.spec.content"`
@@ -145,12 +182,23 @@ type MessageInput struct {
 	Content    []ContentPart `json:"content,omitempty"`
 	ToolCall   *ToolCall     `json:"toolCall,omitempty"`
 	InProgress bool          `json:"inProgress,omitempty"`
+	// Role is the role of a message with content. Content with the assistant role is used as a response of the
+	// assistant instead of generating one.
+	Role RoleType `json:"role,omitempty"`
+	// Seed is mixed into the seed of the completion so that alternative completions of the same messages differ
+	Seed int `json:"seed,omitempty"`
 }
 
 func (in MessageInput) Valid() error {
 	if in.Completion && len(in.Content) > 0 {
 		return fmt.Errorf("either spec.completion or spec.content should be set, but not both")
 	}
+	switch {
+	case in.Role != "" && in.Role != RoleTypeUser && in.Role != RoleTypeAssistant:
+		return fmt.Errorf("spec.role should be %s or %s, not %s", RoleTypeUser, RoleTypeAssistant, in.Role)
+	case in.Role != "" && (in.Completion || in.ToolCall != nil):
+		return fmt.Errorf("spec.role can only be set on messages with content")
+	}
 	return nil
 }
 
@@ -158,17 +206,39 @@ type MessageSpec struct {
 	Input             MessageInput `json:"input,omitempty"`
 	ParentMessageName string       `json:"parentMessageName,omitempty"`
 	FileNames         []string     `json:"fileNames,omitempty"`
-	More              bool         `json:"more,omitempty"`
+	// More means that more messages follow the message, so no response is generated for it and its tool calls
+	// are not invoked
+	More bool `json:"more,omitempty"`
+	// Alternatives is the number of responses of the assistant to the message in addition to the first one.
+	// Each one is generated with a different seed and starts another branch of the thread.
+	Alternatives int `json:"alternatives,omitempty"`
+	// Cancel stops the generation of the message, and the tools and assistants called by it
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+// MessageDelta is content added to a message while it is generated. It is sent by the stream subresource of
+// messages.
+type MessageDelta struct {
+	Text string `json:"text,omitempty"`
 }
 
 type MessageStatus struct {
-	Message         MessageBody        `json:"message,omitempty"`
-	InProgress      bool               `json:"inProgress,omitempty"`
-	RunAfter        *metav1.Time       `json:"runAfter,omitempty"`
-	ThreadName      string             `json:"threadName,omitempty"`
-	NextMessageName string             `json:"nextMessageName,omitempty"`
-	InvokeToolNames []string           `json:"invokeToolNames,omitempty"`
-	Conditions      []metav1.Condition `json:"conditions,omitempty"`
+	Phase           Phase        `json:"phase,omitempty"`
+	Message         MessageBody  `json:"message,omitempty"`
+	InProgress      bool         `json:"inProgress,omitempty"`
+	RunAfter        *metav1.Time `json:"runAfter,omitempty"`
+	Attempts        int          `json:"attempts,omitempty"`
+	SentMessages    int          `json:"sentMessages,omitempty"`
+	DroppedMessages int          `json:"droppedMessages,omitempty"`
+	Usage           *Usage       `json:"usage,omitempty"`
+	ThreadName      string       `json:"threadName,omitempty"`
+	// NextMessageName is the child of the message on the active branch of the thread
+	NextMessageName string `json:"nextMessageName,omitempty"`
+	// ChildMessageNames are all the messages whose parent is this message, in the order they were created.
+	// Each one starts a different branch of the thread.
+	ChildMessageNames []string           `json:"childMessageNames,omitempty"`
+	InvokeToolNames   []string           `json:"invokeToolNames,omitempty"`
+	Conditions        []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
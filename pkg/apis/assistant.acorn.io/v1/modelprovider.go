@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"github.com/acorn-io/baaah/pkg/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	_ conditions.Conditions = (*ModelProvider)(nil)
+)
+
+const (
+	ModelProviderTypeOpenAI    ModelProviderType = "openai"
+	ModelProviderTypeAnthropic ModelProviderType = "anthropic"
+	ModelProviderTypeOllama    ModelProviderType = "ollama"
+)
+
+type ModelProviderType string
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ModelProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ModelProviderSpec   `json:"spec,omitempty"`
+	Status ModelProviderStatus `json:"status,omitempty"`
+}
+
+func (in *ModelProvider) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
+type ModelProviderSpec struct {
+	// Type is the API protocol of the provider, defaults to openai
+	Type               ModelProviderType `json:"type,omitempty"`
+	BaseURL            string            `json:"baseURL,omitempty"`
+	APIKey             *SecretKeyRef     `json:"apiKey,omitempty"`
+	DefaultModel       string            `json:"defaultModel,omitempty"`
+	DefaultVisionModel string            `json:"defaultVisionModel,omitempty"`
+	Headers            map[string]string `json:"headers,omitempty"`
+	// ModelPrefixes selects this provider for assistants in the namespace whose model starts with one of the prefixes
+	ModelPrefixes []string `json:"modelPrefixes,omitempty"`
+	// Default selects this provider for assistants in the namespace that don't otherwise match a provider
+	Default bool `json:"default,omitempty"`
+}
+
+// SecretKeyRef refers to a key of a Credential in the same namespace
+type SecretKeyRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+type ModelProviderStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ModelProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ModelProvider `json:"items"`
+}
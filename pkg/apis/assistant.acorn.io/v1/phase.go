@@ -0,0 +1,21 @@
+package v1
+
+// Phase is the stage of the lifecycle of a message, tool call or thread
+type Phase string
+
+const (
+	// PhasePending is waiting to start, such as a completion waiting to be retried
+	PhasePending Phase = "Pending"
+	// PhaseGenerating is streaming content from the model or a called assistant
+	PhaseGenerating Phase = "Generating"
+	// PhaseAwaitingTools is a message with tool calls that have not all returned
+	PhaseAwaitingTools Phase = "AwaitingTools"
+	PhaseComplete      Phase = "Complete"
+	PhaseFailed        Phase = "Failed"
+	PhaseCancelled     Phase = "Cancelled"
+)
+
+// Done is true when nothing more will happen in the phase
+func (in Phase) Done() bool {
+	return in == PhaseComplete || in == PhaseFailed || in == PhaseCancelled
+}
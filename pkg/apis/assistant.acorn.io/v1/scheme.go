@@ -28,10 +28,15 @@ func AddToSchemeWithGV(scheme *runtime.Scheme, schemeGroupVersion schema.GroupVe
 		&MessageList{},
 		&Thread{},
 		&ThreadList{},
+		&ThreadFork{},
 		&InvokeTool{},
 		&InvokeToolList{},
 		&Image{},
 		&ImageList{},
+		&ModelProvider{},
+		&ModelProviderList{},
+		&Credential{},
+		&CredentialList{},
 		&NoOptions{},
 	)
 
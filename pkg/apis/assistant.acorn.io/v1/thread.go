@@ -22,6 +22,9 @@ type Thread struct {
 }
 
 func (in *Thread) GetDescription() string {
+	if in.Status.Description != "" {
+		return fmt.Sprintf("%s (%s, %d messages)", in.Status.Description, in.Name, in.Status.MessageCount)
+	}
 	return fmt.Sprintf("%s (created %s)", in.Name, in.CreationTimestamp)
 }
 
@@ -29,15 +32,44 @@ func (in *Thread) GetConditions() *[]metav1.Condition {
 	return &in.Status.Conditions
 }
 
+// ThreadAssistantNameField is the field threads are indexed by in the controller to list the threads of an
+// assistant
+const ThreadAssistantNameField = "spec.assistantName"
+
+// Has and Get let field selectors on the assistant name match threads
+func (in *Thread) Has(field string) bool {
+	return field == ThreadAssistantNameField
+}
+
+func (in *Thread) Get(field string) string {
+	if field == ThreadAssistantNameField {
+		return in.Spec.AssistantName
+	}
+	return ""
+}
+
 type ThreadSpec struct {
 	ParentThreadName string `json:"parentThreadName,omitempty"`
 	StartMessageName string `json:"startMessageName,omitempty"`
 	AssistantName    string `json:"assistantName,omitempty"`
+	// ActiveMessageName keeps the thread on the branch that contains the message. It is cleared when a message
+	// is added, the new message starts the active branch.
+	ActiveMessageName string `json:"activeMessageName,omitempty"`
 }
 
 type ThreadStatus struct {
-	Description string             `json:"description,omitempty"`
-	Conditions  []metav1.Condition `json:"conditions,omitempty"`
+	// Phase is the phase of the last message of the thread
+	Phase Phase `json:"phase,omitempty"`
+	// Description is a short title of the thread, generated from its first messages
+	Description     string       `json:"description,omitempty"`
+	LastMessageName string       `json:"lastMessageName,omitempty"`
+	LastMessageTime *metav1.Time `json:"lastMessageTime,omitempty"`
+	MessageCount    int          `json:"messageCount,omitempty"`
+	ToolCallCount   int          `json:"toolCallCount,omitempty"`
+	// DescriptionUsage is the usage of the call that generated the description
+	DescriptionUsage *Usage             `json:"descriptionUsage,omitempty"`
+	Usage            *UsageTotals       `json:"usage,omitempty"`
+	Conditions       []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -48,3 +80,27 @@ type ThreadList struct {
 
 	Items []Thread `json:"items"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ThreadFork is the request and the result of the fork operation of a thread, which copies the messages of the
+// thread up to a message into a new thread
+type ThreadFork struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ThreadForkSpec   `json:"spec,omitempty"`
+	Status ThreadForkStatus `json:"status,omitempty"`
+}
+
+type ThreadForkSpec struct {
+	// MessageName is the last message copied. It defaults to the last message of the thread. The results of a
+	// tool call are copied with it.
+	MessageName string `json:"messageName,omitempty"`
+	// AssistantName is the assistant of the new thread. It defaults to the assistant of the thread.
+	AssistantName string `json:"assistantName,omitempty"`
+}
+
+type ThreadForkStatus struct {
+	ThreadName string `json:"threadName,omitempty"`
+}
@@ -0,0 +1,86 @@
+package v1
+
+// Usage is the number of tokens used to generate a message
+type Usage struct {
+	Model            string `json:"model,omitempty"`
+	PromptTokens     int    `json:"promptTokens,omitempty"`
+	CompletionTokens int    `json:"completionTokens,omitempty"`
+	TotalTokens      int    `json:"totalTokens,omitempty"`
+	// Estimated is true if the provider did not report usage and the tokens were counted by the runtime
+	Estimated bool `json:"estimated,omitempty"`
+	// Cached is true if the response was replayed from a Cache, so the provider was not called
+	Cached bool `json:"cached,omitempty"`
+	// CachedTokens is the number of tokens replayed from a Cache by a message that also called the provider, such
+	// as a cached response with a repair call. They are not included in the other token counts.
+	CachedTokens int `json:"cachedTokens,omitempty"`
+	// Cost is the estimated cost from the price table of the controller. Cached responses have no cost.
+	Cost float64 `json:"cost,omitempty"`
+}
+
+// Add returns the combined usage of two calls. The result is only cached if both calls were cached, otherwise
+// the tokens of a cached call are kept apart in CachedTokens.
+func (in Usage) Add(other Usage) Usage {
+	if in.Cached != other.Cached {
+		in, other = in.live(), other.live()
+	}
+	if in.Model == "" {
+		in.Model = other.Model
+	}
+	in.PromptTokens += other.PromptTokens
+	in.CompletionTokens += other.CompletionTokens
+	in.TotalTokens += other.TotalTokens
+	in.CachedTokens += other.CachedTokens
+	in.Estimated = in.Estimated || other.Estimated
+	in.Cached = in.Cached && other.Cached
+	in.Cost += other.Cost
+	return in
+}
+
+// live returns the usage with the tokens of a cached call moved to CachedTokens
+func (in Usage) live() Usage {
+	if !in.Cached {
+		return in
+	}
+	return Usage{
+		Model:        in.Model,
+		CachedTokens: in.CachedTokens + in.TotalTokens,
+		Estimated:    in.Estimated,
+	}
+}
+
+// UsageTotals is the usage of all the messages of a thread or all the threads of an assistant
+type UsageTotals struct {
+	PromptTokens     int `json:"promptTokens,omitempty"`
+	CompletionTokens int `json:"completionTokens,omitempty"`
+	TotalTokens      int `json:"totalTokens,omitempty"`
+	// CachedTokens is the number of tokens of responses replayed from a Cache. They are not included in the
+	// other token counts or the cost.
+	CachedTokens      int     `json:"cachedTokens,omitempty"`
+	Completions       int     `json:"completions,omitempty"`
+	CachedCompletions int     `json:"cachedCompletions,omitempty"`
+	Cost              float64 `json:"cost,omitempty"`
+}
+
+func (in *UsageTotals) Add(usage Usage) {
+	in.Completions++
+	if usage.Cached {
+		in.CachedCompletions++
+		in.CachedTokens += usage.TotalTokens
+		return
+	}
+	in.CachedTokens += usage.CachedTokens
+	in.PromptTokens += usage.PromptTokens
+	in.CompletionTokens += usage.CompletionTokens
+	in.TotalTokens += usage.TotalTokens
+	in.Cost += usage.Cost
+}
+
+func (in *UsageTotals) AddTotals(totals UsageTotals) {
+	in.PromptTokens += totals.PromptTokens
+	in.CompletionTokens += totals.CompletionTokens
+	in.TotalTokens += totals.TotalTokens
+	in.CachedTokens += totals.CachedTokens
+	in.Completions += totals.Completions
+	in.CachedCompletions += totals.CachedCompletions
+	in.Cost += totals.Cost
+}
@@ -85,11 +85,21 @@ func (in *AssistantSpec) DeepCopyInto(out *AssistantSpec) {
 		*out = new(jsonschema.Schema)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ResponseSchema != nil {
+		in, out := &in.ResponseSchema, &out.ResponseSchema
+		*out = new(jsonschema.Schema)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Cache != nil {
 		in, out := &in.Cache, &out.Cache
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Truncation != nil {
+		in, out := &in.Truncation, &out.Truncation
+		*out = new(TruncationPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssistantSpec.
@@ -105,6 +115,11 @@ func (in *AssistantSpec) DeepCopy() *AssistantSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AssistantStatus) DeepCopyInto(out *AssistantStatus) {
 	*out = *in
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(UsageTotals)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -134,6 +149,12 @@ func (in *Cache) DeepCopyInto(out *Cache) {
 		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(Usage)
+		**out = **in
+	}
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cache.
@@ -186,6 +207,25 @@ func (in *CacheList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheStatus) DeepCopyInto(out *CacheStatus) {
+	*out = *in
+	if in.LastHit != nil {
+		in, out := &in.LastHit, &out.LastHit
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheStatus.
+func (in *CacheStatus) DeepCopy() *CacheStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChatMessageImageURL) DeepCopyInto(out *ChatMessageImageURL) {
 	*out = *in
@@ -226,6 +266,109 @@ func (in *ContentPart) DeepCopy() *ContentPart {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Credential) DeepCopyInto(out *Credential) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credential.
+func (in *Credential) DeepCopy() *Credential {
+	if in == nil {
+		return nil
+	}
+	out := new(Credential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Credential) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialList) DeepCopyInto(out *CredentialList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Credential, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialList.
+func (in *CredentialList) DeepCopy() *CredentialList {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CredentialList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialSpec) DeepCopyInto(out *CredentialSpec) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialSpec.
+func (in *CredentialSpec) DeepCopy() *CredentialSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialStatus) DeepCopyInto(out *CredentialStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialStatus.
+func (in *CredentialStatus) DeepCopy() *CredentialStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionCall) DeepCopyInto(out *FunctionCall) {
 	*out = *in
@@ -425,6 +568,11 @@ func (in *InvokeToolList) DeepCopyObject() runtime.Object {
 func (in *InvokeToolSpec) DeepCopyInto(out *InvokeToolSpec) {
 	*out = *in
 	in.ToolCall.DeepCopyInto(&out.ToolCall)
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvokeToolSpec.
@@ -520,6 +668,21 @@ func (in *MessageBody) DeepCopy() *MessageBody {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MessageDelta) DeepCopyInto(out *MessageDelta) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MessageDelta.
+func (in *MessageDelta) DeepCopy() *MessageDelta {
+	if in == nil {
+		return nil
+	}
+	out := new(MessageDelta)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MessageInput) DeepCopyInto(out *MessageInput) {
 	*out = *in
@@ -608,6 +771,16 @@ func (in *MessageStatus) DeepCopyInto(out *MessageStatus) {
 		in, out := &in.RunAfter, &out.RunAfter
 		*out = (*in).DeepCopy()
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(Usage)
+		**out = **in
+	}
+	if in.ChildMessageNames != nil {
+		in, out := &in.ChildMessageNames, &out.ChildMessageNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.InvokeToolNames != nil {
 		in, out := &in.InvokeToolNames, &out.InvokeToolNames
 		*out = make([]string, len(*in))
@@ -632,6 +805,119 @@ func (in *MessageStatus) DeepCopy() *MessageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelProvider) DeepCopyInto(out *ModelProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelProvider.
+func (in *ModelProvider) DeepCopy() *ModelProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelProviderList) DeepCopyInto(out *ModelProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ModelProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelProviderList.
+func (in *ModelProviderList) DeepCopy() *ModelProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelProviderSpec) DeepCopyInto(out *ModelProviderSpec) {
+	*out = *in
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ModelPrefixes != nil {
+		in, out := &in.ModelPrefixes, &out.ModelPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelProviderSpec.
+func (in *ModelProviderSpec) DeepCopy() *ModelProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelProviderStatus) DeepCopyInto(out *ModelProviderStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelProviderStatus.
+func (in *ModelProviderStatus) DeepCopy() *ModelProviderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelProviderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NoOptions) DeepCopyInto(out *NoOptions) {
 	*out = *in
@@ -656,6 +942,21 @@ func (in *NoOptions) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
+func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Thread) DeepCopyInto(out *Thread) {
 	*out = *in
@@ -683,6 +984,63 @@ func (in *Thread) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreadFork) DeepCopyInto(out *ThreadFork) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreadFork.
+func (in *ThreadFork) DeepCopy() *ThreadFork {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreadFork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThreadFork) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreadForkSpec) DeepCopyInto(out *ThreadForkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreadForkSpec.
+func (in *ThreadForkSpec) DeepCopy() *ThreadForkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreadForkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreadForkStatus) DeepCopyInto(out *ThreadForkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreadForkStatus.
+func (in *ThreadForkStatus) DeepCopy() *ThreadForkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreadForkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThreadList) DeepCopyInto(out *ThreadList) {
 	*out = *in
@@ -733,6 +1091,20 @@ func (in *ThreadSpec) DeepCopy() *ThreadSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThreadStatus) DeepCopyInto(out *ThreadStatus) {
 	*out = *in
+	if in.LastMessageTime != nil {
+		in, out := &in.LastMessageTime, &out.LastMessageTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DescriptionUsage != nil {
+		in, out := &in.DescriptionUsage, &out.DescriptionUsage
+		*out = new(Usage)
+		**out = **in
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(UsageTotals)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -788,3 +1160,48 @@ func (in *ToolCall) DeepCopy() *ToolCall {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TruncationPolicy) DeepCopyInto(out *TruncationPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TruncationPolicy.
+func (in *TruncationPolicy) DeepCopy() *TruncationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TruncationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Usage) DeepCopyInto(out *Usage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Usage.
+func (in *Usage) DeepCopy() *Usage {
+	if in == nil {
+		return nil
+	}
+	out := new(Usage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsageTotals) DeepCopyInto(out *UsageTotals) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageTotals.
+func (in *UsageTotals) DeepCopy() *UsageTotals {
+	if in == nil {
+		return nil
+	}
+	out := new(UsageTotals)
+	in.DeepCopyInto(out)
+	return out
+}
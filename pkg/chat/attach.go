@@ -0,0 +1,77 @@
+package chat
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/vision"
+)
+
+// maxAttachmentSize is the size of the largest image the providers accept
+const maxAttachmentSize = 20 << 20
+
+// attach reads the file at path and adds it to the attachments sent with the next message. Images are stored as
+// an Image and sent as a URL to it, text files are sent as text.
+func (r *run) attach(ctx context.Context, path string) error {
+	part, err := r.attachment(ctx, path)
+	if err != nil {
+		return fmt.Errorf("attaching %s: %w", path, err)
+	}
+	r.attachments = append(r.attachments, part)
+	return nil
+}
+
+func (r *run) attachment(ctx context.Context, path string) (v1.ContentPart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return v1.ContentPart{}, err
+	}
+	if len(data) > maxAttachmentSize {
+		return v1.ContentPart{}, fmt.Errorf("the file is larger than %dMiB", maxAttachmentSize>>20)
+	}
+
+	contentType := detectContentType(path, data)
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		url, err := vision.StoreImage(ctx, r.c, r.Namespace, v1.ChatMessageImageURL{
+			Base64:      base64.StdEncoding.EncodeToString(data),
+			ContentType: contentType,
+		})
+		if err != nil {
+			return v1.ContentPart{}, err
+		}
+		return v1.ContentPart{
+			Image: &v1.ChatMessageImageURL{
+				URL: url,
+			},
+		}, nil
+	case strings.HasPrefix(contentType, "text/") || utf8.Valid(data):
+		return v1.ContentPart{
+			Text: fmt.Sprintf("%s:\n\n```\n%s\n```", filepath.Base(path), strings.TrimRight(string(data), "\n")),
+		}, nil
+	}
+	return v1.ContentPart{}, fmt.Errorf("%s files can't be attached, only images and text", contentType)
+}
+
+// detectContentType returns the content type of the file from its content, or from its extension if the content
+// isn't recognized
+func detectContentType(path string, data []byte) string {
+	contentType := http.DetectContentType(data)
+	if contentType == "application/octet-stream" || strings.HasPrefix(contentType, "text/plain") {
+		if byExt := mime.TypeByExtension(filepath.Ext(path)); byExt != "" {
+			contentType = byExt
+		}
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return contentType
+}
@@ -0,0 +1,149 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/watcher"
+)
+
+// oneShotMessage returns the message to send without prompting, from --message or stdin
+func (r *run) oneShotMessage() (string, error) {
+	if r.Message != "" && r.Message != "-" {
+		return r.Message, nil
+	}
+
+	if r.Message == "" {
+		stat, err := os.Stdin.Stat()
+		if err != nil {
+			return "", err
+		}
+		if stat.Mode()&os.ModeCharDevice != 0 {
+			// A terminal, chat with the user
+			return "", nil
+		}
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	message := strings.TrimSpace(string(data))
+	if message == "" {
+		return "", errors.New("no message on stdin")
+	}
+	return message, nil
+}
+
+// oneShot sends a message, prints the response and returns. The response of the assistant is printed as it is
+// generated, or the body of the response is printed as JSON once it is complete.
+func (r *run) oneShot(ctx context.Context, content string) error {
+	switch {
+	case r.Output != "text" && r.Output != "json":
+		return fmt.Errorf("invalid output %s, must be text or json", r.Output)
+	case r.Assistant == "" && r.Thread == "":
+		return errors.New("--assistant or --thread is required to send a message")
+	}
+
+	thread, err := r.getThread(ctx)
+	if err != nil {
+		return err
+	}
+
+	last, err := r.lastMessage(ctx, thread)
+	if err != nil {
+		return err
+	}
+
+	name, err := r.send(ctx, thread, last, content)
+	if err != nil {
+		return err
+	}
+
+	var (
+		msg     *v1.Message
+		printed bool
+	)
+	for name != "" {
+		var text string
+		msg, err = watcher.New[*v1.Message](r.c).ByName(ctx, r.Namespace, name, func(msg *v1.Message) (bool, error) {
+			switch msg.Status.Phase {
+			case v1.PhaseFailed:
+				return false, messageError(msg)
+			case v1.PhaseCancelled:
+				return false, fmt.Errorf("message %s was cancelled", msg.Name)
+			}
+
+			if r.Output == "text" && msg.Status.Message.Role == v1.RoleTypeAssistant {
+				text = r.printText(msg, text, printed)
+			}
+
+			// Messages other than the responses of the assistant are followed by another message
+			return msg.Status.Phase.Done() &&
+				(msg.Status.NextMessageName != "" || msg.Status.Message.Role == v1.RoleTypeAssistant), nil
+		})
+		if err != nil {
+			return err
+		}
+		printed = printed || text != ""
+		name = msg.Status.NextMessageName
+	}
+
+	if r.Output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(msg.Status.Message)
+	}
+	if printed {
+		fmt.Println()
+	}
+	return nil
+}
+
+// printText prints the text of msg that was not printed yet, and returns the text printed so far
+func (r *run) printText(msg *v1.Message, printed string, separate bool) string {
+	var texts []string
+	for _, content := range msg.Status.Message.Content {
+		if content.Text != "" {
+			texts = append(texts, content.Text)
+		}
+	}
+
+	text := strings.Join(texts, "\n")
+	if text == printed || !strings.HasPrefix(text, printed) {
+		return printed
+	}
+	if printed == "" && separate {
+		// Responses before tool calls are separated from the final response
+		fmt.Println()
+	}
+	fmt.Print(strings.TrimPrefix(text, printed))
+	return text
+}
+
+// lastMessage returns the last message on the active branch of the thread, which has to be a response of the
+// assistant
+func (r *run) lastMessage(ctx context.Context, thread *v1.Thread) (string, error) {
+	var (
+		next = thread.Spec.StartMessageName
+		last v1.Message
+	)
+	for next != "" {
+		if err := r.c.Get(ctx, router.Key(thread.Namespace, next), &last); err != nil {
+			return "", err
+		}
+		next = last.Status.NextMessageName
+	}
+
+	if last.Name != "" && (last.Status.Message.Role != v1.RoleTypeAssistant || !last.Status.Phase.Done()) {
+		return "", fmt.Errorf("thread %s is waiting for the response to message %s", thread.Name, last.Name)
+	}
+	return last.Name, nil
+}
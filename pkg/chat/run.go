@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -13,6 +13,7 @@ import (
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/watcher"
 	"github.com/sirupsen/logrus"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,29 +22,62 @@ import (
 type Options struct {
 	Assistant string
 	Thread    string
-	Namespace string `usage:"Set namespace" short:"n" env:"NAMESPACE"`
+	Namespace string   `usage:"Set namespace" short:"n" env:"NAMESPACE"`
+	Message   string   `usage:"Send the message, print the response and exit. The message is read from stdin if it is - or stdin is not a terminal" short:"m"`
+	Output    string   `usage:"Output of the response to --message, text or json" short:"o"`
+	Attach    []string `usage:"Attach a file to the first message, images are sent as images and text files as text"`
+	TUI       bool     `usage:"Chat in a full-screen terminal UI"`
 }
 
 func (o Options) complete() Options {
 	if o.Namespace == "" {
 		o.Namespace = "local"
 	}
+	if o.Output == "" {
+		o.Output = "text"
+	}
 	return o
 }
 
+// errQuit stops the chat when the user quits
+var errQuit = errors.New("quit")
+
 func Run(ctx context.Context, k kclient.WithWatch, opt Options) error {
 	opt = opt.complete()
 	r := run{
 		Options: opt,
 		c:       k,
 	}
-	return r.run(ctx)
+
+	for _, path := range opt.Attach {
+		if err := r.attach(ctx, path); err != nil {
+			return err
+		}
+	}
+
+	oneShot, err := r.oneShotMessage()
+	if err != nil {
+		return err
+	} else if oneShot != "" {
+		return r.oneShot(ctx, oneShot)
+	}
+
+	if r.TUI {
+		return r.runTUI(ctx)
+	}
+
+	if err := r.run(ctx); !errors.Is(err, errQuit) {
+		return err
+	}
+	return nil
 }
 
 type run struct {
 	Options
 	c      kclient.WithWatch
 	helped bool
+	// attachments are sent with the next message
+	attachments []v1.ContentPart
 }
 
 func (r *run) getPrompt(t *v1.Thread) string {
@@ -66,7 +100,12 @@ func (r *run) getHelp() string {
 			"a) Switch assistant\n" +
 			"q) Quit/Exit\n" +
 			"d) Delete a message and then resume the thread\n" +
-			"dt) Delete a thread and create a new one\n"
+			"b) Switch to another branch of the thread\n" +
+			"g) Generate another response to the last message\n" +
+			"c) Cycle through the responses to the last message\n" +
+			"f) Fork the thread at a message into a new thread\n" +
+			"dt) Delete a thread and create a new one\n" +
+			"/attach PATH) Attach an image or text file to the next message\n"
 	}
 	return ""
 }
@@ -130,6 +169,157 @@ func (r *run) deleteMessage(ctx context.Context, thread *v1.Thread) error {
 	})
 }
 
+func (r *run) switchBranch(ctx context.Context, thread *v1.Thread) error {
+	var alternatives v1.MessageList
+
+	// The alternatives are the children of the messages on the active branch that are not on it
+	next := thread.Spec.StartMessageName
+	for next != "" {
+		var msg v1.Message
+		if err := r.c.Get(ctx, router.Key(thread.Namespace, next), &msg); err != nil {
+			return err
+		}
+		for _, childName := range msg.Status.ChildMessageNames {
+			if childName == msg.Status.NextMessageName {
+				continue
+			}
+			var child v1.Message
+			if err := r.c.Get(ctx, router.Key(thread.Namespace, childName), &child); apierror.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			alternatives.Items = append(alternatives.Items, child)
+		}
+		next = msg.Status.NextMessageName
+	}
+
+	if len(alternatives.Items) == 0 {
+		fmt.Println("No other branches in this thread")
+		return nil
+	}
+
+	msgName, err := selectItem(&alternatives, "Select branch")
+	if err != nil {
+		return err
+	}
+
+	var msg v1.Message
+	if err := r.c.Get(ctx, router.Key(thread.Namespace, msgName), &msg); err != nil {
+		return err
+	}
+
+	return r.activate(ctx, thread, &msg)
+}
+
+// activate switches the thread to the branch that contains msg and waits for the switch
+func (r *run) activate(ctx context.Context, thread *v1.Thread, msg *v1.Message) error {
+	patch := kclient.MergeFrom(thread.DeepCopy())
+	thread.Spec.ActiveMessageName = msg.Name
+	if err := r.c.Patch(ctx, thread, patch); err != nil {
+		return err
+	}
+
+	_, err := watcher.New[*v1.Message](r.c).ByName(ctx, thread.Namespace, msg.Spec.ParentMessageName, func(parent *v1.Message) (bool, error) {
+		return parent.Status.NextMessageName == msg.Name, nil
+	})
+	return err
+}
+
+// regenerate asks for another response to the message msg responded to
+func (r *run) regenerate(ctx context.Context, msg *v1.Message) (string, error) {
+	if msg == nil || !msg.Spec.Input.Completion {
+		fmt.Println("No response to regenerate")
+		return "", nil
+	}
+
+	var response v1.Message
+	if err := r.c.SubResource("regenerate").Create(ctx, msg, &response); err != nil {
+		return "", err
+	}
+	return response.Name, nil
+}
+
+// cycle switches the thread to the next alternative of msg
+func (r *run) cycle(ctx context.Context, thread *v1.Thread, msg *v1.Message) (string, error) {
+	if msg == nil || msg.Spec.ParentMessageName == "" {
+		fmt.Println("No alternatives to switch to")
+		return "", nil
+	}
+
+	var parent v1.Message
+	if err := r.c.Get(ctx, router.Key(msg.Namespace, msg.Spec.ParentMessageName), &parent); err != nil {
+		return "", err
+	}
+
+	alternatives := parent.Status.ChildMessageNames
+	if len(alternatives) < 2 {
+		fmt.Println("No alternatives to switch to")
+		return "", nil
+	}
+
+	i := (slices.Index(alternatives, msg.Name) + 1) % len(alternatives)
+	var next v1.Message
+	if err := r.c.Get(ctx, router.Key(msg.Namespace, alternatives[i]), &next); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Alternative %d of %d\n", i+1, len(alternatives))
+	if err := r.activate(ctx, thread, &next); err != nil {
+		return "", err
+	}
+	return next.Name, nil
+}
+
+// fork copies the thread up to a message into a new thread, which becomes the current thread
+func (r *run) fork(ctx context.Context, thread *v1.Thread) error {
+	var msgs v1.MessageList
+
+	next := thread.Spec.StartMessageName
+	for next != "" {
+		var msg v1.Message
+		if err := r.c.Get(ctx, router.Key(thread.Namespace, next), &msg); err != nil {
+			return err
+		}
+		msgs.Items = append(msgs.Items, msg)
+		next = msg.Status.NextMessageName
+	}
+
+	if len(msgs.Items) == 0 {
+		fmt.Println("No messages to fork")
+		return nil
+	}
+
+	msgName, err := selectItem(&msgs, "Fork after message")
+	if err != nil {
+		return err
+	}
+
+	var assistants v1.AssistantList
+	if err := r.c.List(ctx, &assistants, &kclient.ListOptions{
+		Namespace: r.Namespace,
+	}); err != nil {
+		return err
+	}
+
+	assistant, err := selectItem(&assistants, "Choose an assistant for the new thread")
+	if err != nil {
+		return err
+	}
+
+	fork := &v1.ThreadFork{
+		Spec: v1.ThreadForkSpec{
+			MessageName:   msgName,
+			AssistantName: assistant,
+		},
+	}
+	if err := r.c.SubResource("fork").Create(ctx, thread, fork); err != nil {
+		return err
+	}
+
+	return r.c.Get(ctx, router.Key(thread.Namespace, fork.Status.ThreadName), thread)
+}
+
 func (r *run) selectThread(ctx context.Context, thread *v1.Thread, printEmpty bool) error {
 	var (
 		threads  v1.ThreadList
@@ -187,6 +377,15 @@ func (r *run) nextMessage(ctx context.Context, thread *v1.Thread, msg *v1.Messag
 		}
 	}
 
+	if path, ok := strings.CutPrefix(content, "/attach "); ok {
+		if err := r.attach(ctx, strings.TrimSpace(path)); err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("Attached %s, it is sent with the next message\n", strings.TrimSpace(path))
+		}
+		return r.nextMessage(ctx, thread, msg)
+	}
+
 	switch content {
 	case "dt":
 		if err := r.deleteThread(ctx, thread); err != nil {
@@ -201,6 +400,39 @@ func (r *run) nextMessage(ctx context.Context, thread *v1.Thread, msg *v1.Messag
 			return thread.Spec.StartMessageName, nil
 		}
 		return r.nextMessage(ctx, thread, nil)
+	case "b":
+		if err := r.switchBranch(ctx, thread); err != nil {
+			return "", err
+		}
+		if thread.Spec.StartMessageName != "" {
+			return thread.Spec.StartMessageName, nil
+		}
+		return r.nextMessage(ctx, thread, nil)
+	case "f":
+		if err := r.fork(ctx, thread); err != nil {
+			return "", err
+		}
+		if thread.Spec.StartMessageName != "" {
+			return thread.Spec.StartMessageName, nil
+		}
+		return r.nextMessage(ctx, thread, nil)
+	case "g", "c":
+		var (
+			next string
+			err  error
+		)
+		if content == "g" {
+			next, err = r.regenerate(ctx, msg)
+		} else {
+			next, err = r.cycle(ctx, thread, msg)
+		}
+		if err != nil {
+			return "", err
+		}
+		if next != "" {
+			return next, nil
+		}
+		return r.nextMessage(ctx, thread, msg)
 	case "n":
 		*thread = *r.emptyThread(thread.Spec.AssistantName)
 		return r.nextMessage(ctx, thread, nil)
@@ -213,7 +445,7 @@ func (r *run) nextMessage(ctx context.Context, thread *v1.Thread, msg *v1.Messag
 		}
 		return r.nextMessage(ctx, thread, nil)
 	case "q":
-		os.Exit(0)
+		return "", errQuit
 	case "a":
 		r.Options.Assistant = ""
 		r.Options.Thread = ""
@@ -230,14 +462,31 @@ func (r *run) nextMessage(ctx context.Context, thread *v1.Thread, msg *v1.Messag
 		parentMessage = msg.Name
 	}
 
-	msg = &v1.Message{
+	return r.send(ctx, thread, parentMessage, content)
+}
+
+// send adds a message with content, and the files attached to it, to the thread after the parent message, and
+// returns its name. The thread is created if this is its first message.
+func (r *run) send(ctx context.Context, thread *v1.Thread, parentMessage, content string) (string, error) {
+	msg, err := r.sendContent(ctx, thread, parentMessage, append(slices.Clone(r.attachments), v1.Text(content)...))
+	if err != nil {
+		return "", err
+	}
+	r.attachments = nil
+	return msg.Name, nil
+}
+
+// sendContent adds a message with the content to the thread after the parent message. The thread is created if
+// this is its first message.
+func (r *run) sendContent(ctx context.Context, thread *v1.Thread, parentMessage string, content []v1.ContentPart) (*v1.Message, error) {
+	msg := &v1.Message{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "m-",
 			Namespace:    thread.Namespace,
 		},
 		Spec: v1.MessageSpec{
 			Input: v1.MessageInput{
-				Content: v1.Text(content),
+				Content: content,
 			},
 			ParentMessageName: parentMessage,
 		},
@@ -245,21 +494,21 @@ func (r *run) nextMessage(ctx context.Context, thread *v1.Thread, msg *v1.Messag
 	}
 
 	if err := r.c.Create(ctx, msg); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if thread.Spec.StartMessageName == "" {
 		thread.Spec.StartMessageName = msg.Name
 		if thread.UID == "" {
 			if err := r.c.Create(ctx, thread); err != nil {
-				return "", err
+				return nil, err
 			}
 		} else if err := r.c.Update(ctx, thread); err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
-	return msg.Name, nil
+	return msg, nil
 }
 
 func (r *run) run(ctx context.Context) error {
@@ -283,32 +532,27 @@ func (r *run) printMessage(ctx context.Context, t *v1.Thread, name string) error
 		if name != "" {
 			logrus.Debugf("Waiting on message %s", name)
 			msg, err = w.ByName(ctx, r.Namespace, name, func(msg *v1.Message) (bool, error) {
-				content := msg.Status.Message.String()
-
-				if condition := meta.FindStatusCondition(msg.Status.Conditions, "Controller"); condition != nil && condition.Status == metav1.ConditionFalse &&
-					condition.Message != "" {
-					return false, errors.New(condition.Message)
+				if msg.Status.Phase == v1.PhaseFailed {
+					return false, messageError(msg)
 				}
 
-				if content == "" {
-					return false, nil
+				content := msg.Status.Message.String()
+				if content == "" && msg.Status.Phase == v1.PhaseCancelled {
+					content = "(cancelled)"
 				}
-
-				content = fmt.Sprintf("[%s]: %s", msg.Name, content)
-
-				fmt.Print(strings.TrimPrefix(content, printed))
-				printed = content
-
-				if msg.Status.InProgress {
-					return false, nil
+				if content != "" {
+					content = fmt.Sprintf("[%s]: %s", msg.Name, content)
+					fmt.Print(strings.TrimPrefix(content, printed))
+					printed = content
 				}
 
-				// It's done when it's an assistant message, or we have the next message
-				if msg.Status.NextMessageName != "" || (msg.Status.Message.Role == v1.RoleTypeAssistant && msg.Status.Message.ToolCall == nil) {
-					fmt.Println()
-					return true, nil
+				// Messages other than the responses of the assistant are followed by another message
+				if !msg.Status.Phase.Done() ||
+					(msg.Status.NextMessageName == "" && msg.Status.Message.Role != v1.RoleTypeAssistant) {
+					return false, nil
 				}
-				return false, nil
+				fmt.Println()
+				return true, nil
 			})
 			if err != nil {
 				return err
@@ -328,6 +572,15 @@ func (r *run) printMessage(ctx context.Context, t *v1.Thread, name string) error
 	}
 }
 
+// messageError returns the error of a failed message
+func messageError(msg *v1.Message) error {
+	if condition := meta.FindStatusCondition(msg.Status.Conditions, "Controller"); condition != nil &&
+		condition.Status == metav1.ConditionFalse && condition.Message != "" {
+		return errors.New(condition.Message)
+	}
+	return fmt.Errorf("message %s failed", msg.Name)
+}
+
 func (r *run) printThread(ctx context.Context, t *v1.Thread) error {
 	return r.printMessage(ctx, t, t.Spec.StartMessageName)
 }
@@ -0,0 +1,721 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sirupsen/logrus"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// refreshInterval is how often the TUI loads the thread when nothing changed, in case a watch misses a change
+const refreshInterval = 2 * time.Second
+
+type pane int
+
+const (
+	paneInput pane = iota
+	paneTranscript
+	paneThreads
+	paneAssistants
+	panes
+)
+
+// refreshMsg asks the TUI to load the thread again
+type refreshMsg struct{}
+
+// tickMsg asks the TUI to load the thread again, and to keep doing so
+type tickMsg struct{}
+
+// loadedMsg is the state loaded for a generation of the TUI. Generations change with the thread, so the state of
+// a thread that was left is dropped.
+type loadedMsg struct {
+	gen      int
+	snapshot *snapshot
+	err      error
+}
+
+// doneMsg is the result of an action, which may switch to another thread
+type doneMsg struct {
+	status string
+	thread *v1.Thread
+	err    error
+}
+
+// snapshot is what the TUI shows of the namespace
+type snapshot struct {
+	thread v1.Thread
+	// msgs are the messages on the active branch of the thread
+	msgs []v1.Message
+	// invokes are the tool calls of the messages shown, by the key of their section
+	invokes map[string]v1.InvokeTool
+	// subThreads are the messages of the threads of called assistants, for the sections that are expanded
+	subThreads map[string][]v1.Message
+	threads    []v1.Thread
+	assistants []v1.Assistant
+}
+
+type tui struct {
+	r   *run
+	ctx context.Context
+
+	thread  v1.Thread
+	gen     int
+	state   snapshot
+	loading bool
+	// dirty is set when something changed while the thread was loading
+	dirty bool
+
+	focus           pane
+	hideSide        bool
+	assistantCursor int
+	threadCursor    int
+	// expanded are the keys of the tool call and sub-thread sections that are expanded
+	expanded    map[string]bool
+	sections    []section
+	selectedKey string
+
+	viewport viewport.Model
+	input    textarea.Model
+	// markdown are the renderers of Markdown by the width they wrap to
+	markdown map[int]*glamour.TermRenderer
+	style    string
+	rendered map[string]string
+	// editing is the message that is replaced by the next message sent
+	editing *v1.Message
+	status  string
+	isError bool
+	width   int
+	height  int
+}
+
+// runTUI chats in a full-screen terminal UI until the user quits
+func (r *run) runTUI(ctx context.Context) error {
+	thread, err := r.tuiThread(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Anything logged would be drawn over the UI
+	out := logrus.StandardLogger().Out
+	logrus.SetOutput(io.Discard)
+	defer logrus.SetOutput(out)
+
+	input := textarea.New()
+	input.Placeholder = "Send a message, alt+enter for a new line, /attach PATH to attach a file"
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+	input.KeyMap.InsertNewline = key.NewBinding(key.WithKeys("alt+enter", "ctrl+j"))
+	input.Focus()
+
+	style := "light"
+	if lipgloss.HasDarkBackground() {
+		style = "dark"
+	}
+
+	m := &tui{
+		r:        r,
+		ctx:      ctx,
+		thread:   *thread,
+		expanded: map[string]bool{},
+		rendered: map[string]string{},
+		viewport: viewport.New(0, 0),
+		input:    input,
+		style:    style,
+	}
+	if thread.Spec.AssistantName == "" {
+		m.setFocus(paneAssistants)
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithContext(ctx))
+	go m.watch(ctx, p)
+
+	if _, err := p.Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return err
+	}
+	return nil
+}
+
+// tuiThread returns the thread to start with. The assistant is chosen in the UI if none is given.
+func (r *run) tuiThread(ctx context.Context) (*v1.Thread, error) {
+	if r.Thread != "" || r.Assistant != "" {
+		return r.getThread(ctx)
+	}
+	return r.emptyThread(""), nil
+}
+
+// watch refreshes the TUI when messages change, which is how generated content is shown as it streams in
+func (m *tui) watch(ctx context.Context, p *tea.Program) {
+	for ctx.Err() == nil {
+		w, err := m.r.c.Watch(ctx, &v1.MessageList{}, kclient.InNamespace(m.r.Namespace))
+		if err != nil {
+			time.Sleep(refreshInterval)
+			continue
+		}
+		for range w.ResultChan() {
+			p.Send(refreshMsg{})
+		}
+		w.Stop()
+	}
+}
+
+func (m *tui) Init() tea.Cmd {
+	m.loading = true
+	return tea.Batch(m.load(), textarea.Blink, tick())
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(time.Time) tea.Msg {
+		return tickMsg{}
+	})
+}
+
+func (m *tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.layout()
+		return m, nil
+	case tea.KeyMsg:
+		return m, m.key(msg)
+	case tea.MouseMsg:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	case tickMsg:
+		return m, tea.Batch(m.refresh(), tick())
+	case refreshMsg:
+		return m, m.refresh()
+	case loadedMsg:
+		return m, m.loaded(msg)
+	case doneMsg:
+		return m, m.done(msg)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// refresh loads the thread, or loads it again once the load in progress is done
+func (m *tui) refresh() tea.Cmd {
+	if m.loading {
+		m.dirty = true
+		return nil
+	}
+	m.loading = true
+	return m.load()
+}
+
+// load loads the thread in the background
+func (m *tui) load() tea.Cmd {
+	var (
+		r        = m.r
+		ctx      = m.ctx
+		gen      = m.gen
+		thread   = m.thread.DeepCopy()
+		expanded = make(map[string]bool, len(m.expanded))
+	)
+	for k, v := range m.expanded {
+		expanded[k] = v
+	}
+	return func() tea.Msg {
+		s, err := r.snapshot(ctx, thread, expanded)
+		return loadedMsg{gen: gen, snapshot: s, err: err}
+	}
+}
+
+func (m *tui) loaded(msg loadedMsg) tea.Cmd {
+	if msg.gen != m.gen {
+		// A load of the thread that was left, the load of the current one is on its way
+		return nil
+	}
+	m.loading = false
+
+	if msg.err != nil {
+		m.setStatus(msg.err)
+	} else {
+		m.state = *msg.snapshot
+		m.thread = msg.snapshot.thread
+		m.assistantCursor = min(m.assistantCursor, max(len(m.state.assistants)-1, 0))
+		m.threadCursor = min(m.threadCursor, len(m.state.threads))
+		m.render()
+	}
+
+	if m.dirty {
+		m.dirty = false
+		m.loading = true
+		return m.load()
+	}
+	return nil
+}
+
+func (m *tui) done(msg doneMsg) tea.Cmd {
+	// The progress of the thread is shown in the header once the request is done
+	if msg.err != nil {
+		m.setStatus(msg.err)
+	} else {
+		m.setStatus(msg.status)
+	}
+	if msg.thread != nil && (msg.thread.Name != m.thread.Name || msg.thread.Name == "") {
+		return m.switchThread(msg.thread)
+	} else if msg.thread != nil {
+		m.thread = *msg.thread
+	}
+	return m.refresh()
+}
+
+// switchThread shows another thread
+func (m *tui) switchThread(thread *v1.Thread) tea.Cmd {
+	m.thread = *thread
+	m.gen++
+	m.state.msgs = nil
+	m.state.thread = *thread
+	m.editing = nil
+	m.selectedKey = ""
+	m.render()
+	m.loading = true
+	return m.load()
+}
+
+func (m *tui) setStatus(status any) {
+	switch status := status.(type) {
+	case error:
+		m.status, m.isError = status.Error(), true
+	default:
+		m.status, m.isError = fmt.Sprint(status), false
+	}
+}
+
+func (m *tui) setFocus(focus pane) {
+	if m.hideSide && (focus == paneThreads || focus == paneAssistants) {
+		focus = paneInput
+	}
+	m.focus = focus
+	if focus == paneInput {
+		m.input.Focus()
+	} else {
+		m.input.Blur()
+	}
+	m.render()
+}
+
+func (m *tui) key(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit
+	case "tab":
+		m.setFocus((m.focus + 1) % panes)
+		return nil
+	case "shift+tab":
+		m.setFocus((m.focus + panes - 1) % panes)
+		return nil
+	case "ctrl+t":
+		m.hideSide = !m.hideSide
+		m.setFocus(m.focus)
+		m.layout()
+		return nil
+	case "ctrl+n":
+		m.setStatus("New thread")
+		return m.switchThread(m.r.emptyThread(m.thread.Spec.AssistantName))
+	case "ctrl+o":
+		m.toggleAll()
+		return m.refresh()
+	case "ctrl+r":
+		return m.regenerate()
+	case "ctrl+e":
+		m.edit()
+		return nil
+	case "ctrl+f":
+		return m.fork()
+	case "ctrl+x":
+		return m.cancel()
+	case "esc":
+		if m.editing != nil {
+			m.editing = nil
+			m.input.Reset()
+			m.setStatus("Stopped editing")
+		}
+		m.setFocus(paneInput)
+		return nil
+	}
+
+	switch m.focus {
+	case paneInput:
+		if msg.String() == "enter" {
+			return m.submit()
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return cmd
+	case paneTranscript:
+		switch msg.String() {
+		case "up", "k":
+			m.selectSection(-1)
+		case "down", "j":
+			m.selectSection(1)
+		case "enter", " ":
+			if m.selectedKey != "" {
+				m.expanded[m.selectedKey] = !m.expanded[m.selectedKey]
+				m.render()
+				return m.refresh()
+			}
+		default:
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return cmd
+		}
+	case paneThreads:
+		switch msg.String() {
+		case "up", "k":
+			m.threadCursor = max(m.threadCursor-1, 0)
+		case "down", "j":
+			m.threadCursor = min(m.threadCursor+1, len(m.state.threads))
+		case "enter":
+			m.setFocus(paneInput)
+			if m.threadCursor == 0 {
+				return m.switchThread(m.r.emptyThread(m.thread.Spec.AssistantName))
+			}
+			thread := m.state.threads[m.threadCursor-1]
+			return m.switchThread(&thread)
+		}
+	case paneAssistants:
+		switch msg.String() {
+		case "up", "k":
+			m.assistantCursor = max(m.assistantCursor-1, 0)
+		case "down", "j":
+			m.assistantCursor = min(m.assistantCursor+1, max(len(m.state.assistants)-1, 0))
+		case "enter":
+			if len(m.state.assistants) == 0 {
+				return nil
+			}
+			m.threadCursor = 0
+			m.setFocus(paneInput)
+			return m.switchThread(m.r.emptyThread(m.state.assistants[m.assistantCursor].Name))
+		}
+	}
+	return nil
+}
+
+// last returns the last message on the active branch
+func (m *tui) last() *v1.Message {
+	if len(m.state.msgs) == 0 {
+		return nil
+	}
+	return &m.state.msgs[len(m.state.msgs)-1]
+}
+
+// busy is true while the assistant has not responded to the last message
+func (m *tui) busy() bool {
+	last := m.last()
+	return last != nil && (!last.Status.Phase.Done() || last.Status.Message.Role != v1.RoleTypeAssistant)
+}
+
+func (m *tui) submit() tea.Cmd {
+	content := strings.TrimSpace(m.input.Value())
+	if content == "" {
+		return nil
+	}
+
+	if path, ok := strings.CutPrefix(content, "/attach "); ok {
+		path = strings.TrimSpace(path)
+		if err := m.r.attach(m.ctx, path); err != nil {
+			m.setStatus(err)
+		} else {
+			m.setStatus(fmt.Sprintf("Attached %s, it is sent with the next message", path))
+		}
+		m.input.Reset()
+		return nil
+	}
+
+	var (
+		r           = m.r
+		ctx         = m.ctx
+		thread      = m.thread.DeepCopy()
+		editing     = m.editing
+		parent      string
+		attachments = m.r.attachments
+	)
+	switch {
+	case thread.Spec.AssistantName == "":
+		m.setStatus(errors.New("choose an assistant first"))
+		return nil
+	case editing != nil && editing.Spec.ParentMessageName == "":
+		// The first message has no parent to add another branch to, so it starts a new thread
+		thread = r.emptyThread(thread.Spec.AssistantName)
+		editing = nil
+	case editing != nil:
+		parent = editing.Spec.ParentMessageName
+	case m.busy():
+		m.setStatus(errors.New("wait for the response, cancel it with ctrl+x or regenerate it with ctrl+r"))
+		return nil
+	case m.last() != nil:
+		parent = m.last().Name
+	}
+
+	m.input.Reset()
+	m.editing = nil
+	m.r.attachments = nil
+	m.setStatus("")
+
+	return func() tea.Msg {
+		msg, err := r.sendContent(ctx, thread, parent, append(slices.Clone(attachments), v1.Text(content)...))
+		if err != nil {
+			return doneMsg{err: err}
+		}
+		if editing != nil {
+			if err := r.activate(ctx, thread, msg); err != nil {
+				return doneMsg{err: err}
+			}
+		}
+		return doneMsg{thread: thread}
+	}
+}
+
+// edit puts the last message of the user in the input, the message sent replaces it on a new branch
+func (m *tui) edit() {
+	for i := len(m.state.msgs) - 1; i >= 0; i-- {
+		msg := m.state.msgs[i]
+		if msg.Status.Message.Role != v1.RoleTypeUser {
+			continue
+		}
+		var texts []string
+		for _, content := range msg.Spec.Input.Content {
+			if content.Text != "" {
+				texts = append(texts, content.Text)
+			}
+		}
+		m.editing = &msg
+		m.input.SetValue(strings.Join(texts, "\n"))
+		m.setFocus(paneInput)
+		m.setStatus(fmt.Sprintf("Editing %s, esc to stop", msg.Name))
+		return
+	}
+	m.setStatus(errors.New("no message to edit"))
+}
+
+func (m *tui) regenerate() tea.Cmd {
+	last := m.last()
+	if last == nil || !last.Spec.Input.Completion {
+		m.setStatus(errors.New("no response to regenerate"))
+		return nil
+	}
+	r, ctx, msg := m.r, m.ctx, last.DeepCopy()
+	m.setStatus("Regenerating")
+	return func() tea.Msg {
+		_, err := r.regenerate(ctx, msg)
+		return doneMsg{err: err}
+	}
+}
+
+// fork copies the active branch of the thread into a new thread of the same assistant
+func (m *tui) fork() tea.Cmd {
+	last := m.last()
+	if last == nil || m.thread.Name == "" {
+		m.setStatus(errors.New("no messages to fork"))
+		return nil
+	}
+	r, ctx, thread := m.r, m.ctx, m.thread.DeepCopy()
+	fork := &v1.ThreadFork{
+		Spec: v1.ThreadForkSpec{
+			MessageName:   last.Name,
+			AssistantName: thread.Spec.AssistantName,
+		},
+	}
+	return func() tea.Msg {
+		if err := r.c.SubResource("fork").Create(ctx, thread, fork); err != nil {
+			return doneMsg{err: err}
+		}
+		var forked v1.Thread
+		if err := r.c.Get(ctx, router.Key(thread.Namespace, fork.Status.ThreadName), &forked); err != nil {
+			return doneMsg{err: err}
+		}
+		return doneMsg{thread: &forked, status: fmt.Sprintf("Forked %s into %s", thread.Name, forked.Name)}
+	}
+}
+
+// cancel stops the generation of the last message, and the tools and assistants it called
+func (m *tui) cancel() tea.Cmd {
+	last := m.last()
+	if last == nil || last.Status.Phase.Done() {
+		m.setStatus(errors.New("nothing to cancel"))
+		return nil
+	}
+	r, ctx, msg := m.r, m.ctx, last.DeepCopy()
+	m.setStatus("Cancelling")
+	return func() tea.Msg {
+		patch := kclient.MergeFrom(msg.DeepCopy())
+		msg.Spec.Cancel = true
+		return doneMsg{err: r.c.Patch(ctx, msg, patch)}
+	}
+}
+
+// toggleAll expands all sections, or collapses them if they are all expanded
+func (m *tui) toggleAll() {
+	expand := false
+	for _, section := range m.sections {
+		if !m.expanded[section.key] {
+			expand = true
+		}
+	}
+	for _, section := range m.sections {
+		m.expanded[section.key] = expand
+	}
+	m.render()
+}
+
+// selectSection moves the selection to another collapsible section of the transcript, and scrolls to it
+func (m *tui) selectSection(delta int) {
+	if len(m.sections) == 0 {
+		return
+	}
+	i := slices.IndexFunc(m.sections, func(s section) bool {
+		return s.key == m.selectedKey
+	})
+	switch {
+	case i < 0 && delta < 0:
+		i = len(m.sections) - 1
+	case i < 0:
+		i = 0
+	default:
+		i = min(max(i+delta, 0), len(m.sections)-1)
+	}
+	m.selectedKey = m.sections[i].key
+	m.render()
+
+	line := m.sections[i].line
+	if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(max(line-m.viewport.Height/3, 0))
+	}
+}
+
+// snapshot loads the thread, the tool calls and sub-threads to show, and the threads and assistants to choose
+// from
+func (r *run) snapshot(ctx context.Context, thread *v1.Thread, expanded map[string]bool) (*snapshot, error) {
+	s := &snapshot{
+		invokes:    map[string]v1.InvokeTool{},
+		subThreads: map[string][]v1.Message{},
+	}
+
+	if thread.Name != "" {
+		if err := r.c.Get(ctx, router.Key(thread.Namespace, thread.Name), thread); apierror.IsNotFound(err) {
+			thread = r.emptyThread(thread.Spec.AssistantName)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	s.thread = *thread
+
+	msgs, err := r.branch(ctx, thread.Spec.StartMessageName)
+	if err != nil {
+		return nil, err
+	}
+	s.msgs = msgs
+	if err := r.loadCalls(ctx, s, msgs, expanded); err != nil {
+		return nil, err
+	}
+
+	var threads v1.ThreadList
+	if err := r.c.List(ctx, &threads, &kclient.ListOptions{
+		Namespace: r.Namespace,
+	}); err != nil {
+		return nil, err
+	}
+	for _, t := range threads.Items {
+		if t.Spec.AssistantName == thread.Spec.AssistantName && t.Spec.ParentThreadName == "" {
+			s.threads = append(s.threads, t)
+		}
+	}
+	slices.SortStableFunc(s.threads, func(a, b v1.Thread) int {
+		return lastActive(&b).Compare(lastActive(&a))
+	})
+
+	var assistants v1.AssistantList
+	if err := r.c.List(ctx, &assistants, &kclient.ListOptions{
+		Namespace: r.Namespace,
+	}); err != nil {
+		return nil, err
+	}
+	s.assistants = assistants.Items
+	slices.SortFunc(s.assistants, func(a, b v1.Assistant) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return s, nil
+}
+
+// loadCalls loads the tool calls of the messages, and the messages of the assistants called by the sections that
+// are expanded
+func (r *run) loadCalls(ctx context.Context, s *snapshot, msgs []v1.Message, expanded map[string]bool) error {
+	for _, msg := range msgs {
+		for _, name := range msg.Status.InvokeToolNames {
+			var invoke v1.InvokeTool
+			if err := r.c.Get(ctx, router.Key(msg.Namespace, name), &invoke); apierror.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			key := callKey(&msg, invoke.Spec.ToolCall.ID)
+			s.invokes[key] = invoke
+			if !expanded[key] || invoke.Status.AssistantMessageName == "" {
+				continue
+			}
+
+			sub, err := r.branch(ctx, invoke.Status.AssistantMessageName)
+			if err != nil {
+				return err
+			}
+			s.subThreads[key] = sub
+			if err := r.loadCalls(ctx, s, sub, expanded); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// branch returns the messages on the active branch from the message named start
+func (r *run) branch(ctx context.Context, start string) (result []v1.Message, _ error) {
+	for next := start; next != ""; {
+		var msg v1.Message
+		if err := r.c.Get(ctx, router.Key(r.Namespace, next), &msg); apierror.IsNotFound(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, msg)
+		next = msg.Status.NextMessageName
+	}
+	return result, nil
+}
+
+// callKey is the key of the section of a tool call made by msg
+func callKey(msg *v1.Message, callID string) string {
+	return msg.Name + "/" + callID
+}
+
+// lastActive is the time of the last message of the thread, or when it was created if it has none
+func lastActive(thread *v1.Thread) time.Time {
+	if thread.Status.LastMessageTime != nil {
+		return thread.Status.LastMessageTime.Time
+	}
+	return thread.CreationTimestamp.Time
+}
@@ -0,0 +1,362 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	reflowtruncate "github.com/muesli/reflow/truncate"
+)
+
+const (
+	sideWidth   = 32
+	inputHeight = 3
+	keyHelp     = "enter send • tab focus • ctrl+r regenerate • ctrl+e edit • ctrl+f fork • ctrl+x cancel • " +
+		"ctrl+n new • ctrl+o expand • ctrl+t panes • ctrl+c quit"
+)
+
+var (
+	userStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	assistantStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	toolStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	dimStyle       = lipgloss.NewStyle().Faint(true)
+	errorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	selectedStyle  = lipgloss.NewStyle().Reverse(true)
+	titleStyle     = lipgloss.NewStyle().Bold(true)
+	paneStyle      = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8"))
+	focusedStyle   = paneStyle.Copy().BorderForeground(lipgloss.Color("12"))
+)
+
+// section is a collapsible part of the transcript, a tool call with the thread of the assistant it called or the
+// result of a tool
+type section struct {
+	key  string
+	line int
+}
+
+// transcript builds the content of the transcript, and keeps track of the lines its sections start on
+type transcript struct {
+	strings.Builder
+	lines    int
+	sections []section
+}
+
+func (t *transcript) line(s string) {
+	t.WriteString(s)
+	t.WriteString("\n")
+	t.lines += strings.Count(s, "\n") + 1
+}
+
+// paneStyle returns a copy of the style of pane p, styles share their rules so they are sized on copies only
+func (m *tui) paneStyle(p pane) lipgloss.Style {
+	if m.focus == p {
+		return focusedStyle.Copy()
+	}
+	return paneStyle.Copy()
+}
+
+// layout sizes the transcript and the input to the terminal
+func (m *tui) layout() {
+	if m.width == 0 {
+		return
+	}
+
+	width := m.width
+	if !m.hideSide {
+		width -= sideWidth
+	}
+	// Borders of the transcript and input, the header and the footer
+	m.viewport.Width = max(width-2, 10)
+	m.viewport.Height = max(m.height-inputHeight-6, 3)
+	m.input.SetWidth(m.viewport.Width)
+	m.input.SetHeight(inputHeight)
+
+	m.markdown = map[int]*glamour.TermRenderer{}
+	m.rendered = map[string]string{}
+	m.render()
+}
+
+// render renders the transcript into the viewport, which stays at the bottom if it was there
+func (m *tui) render() {
+	if m.markdown == nil {
+		return
+	}
+
+	t := &transcript{}
+	for i := range m.state.msgs {
+		m.renderMessage(t, &m.state.msgs[i], "", m.viewport.Width)
+	}
+	if len(m.state.msgs) == 0 {
+		t.line(dimStyle.Render("No messages yet"))
+	}
+	if len(m.r.attachments) > 0 {
+		t.line(dimStyle.Render(fmt.Sprintf("%d attachments are sent with the next message", len(m.r.attachments))))
+	}
+	m.sections = t.sections
+
+	atBottom := m.viewport.AtBottom()
+	m.viewport.SetContent(strings.TrimRight(t.String(), "\n"))
+	if atBottom {
+		m.viewport.GotoBottom()
+	}
+}
+
+func (m *tui) renderMessage(t *transcript, msg *v1.Message, indent string, width int) {
+	body := msg.Status.Message
+	if !body.HasContent() && msg.Status.Phase.Done() && msg.Status.Phase != v1.PhaseFailed {
+		return
+	}
+
+	switch body.Role {
+	case v1.RoleTypeUser:
+		t.line(indent + userStyle.Render("You") + " " + dimStyle.Render(msg.CreationTimestamp.Local().Format("15:04")))
+	case v1.RoleTypeTool:
+		m.renderToolResult(t, msg, indent, width)
+		return
+	default:
+		name := m.state.thread.Spec.AssistantName
+		if indent != "" {
+			name = "Called assistant"
+		}
+		t.line(indent + assistantStyle.Render(name) + " " + dimStyle.Render(msg.CreationTimestamp.Local().Format("15:04")))
+	}
+
+	for _, content := range body.Content {
+		switch {
+		case content.ToolCall != nil:
+			m.renderToolCall(t, msg, content.ToolCall, indent, width)
+		case content.Image != nil && content.Image.URL != "":
+			t.line(indent + dimStyle.Render("[image] "+content.Image.URL))
+		case content.Image != nil:
+			t.line(indent + dimStyle.Render("[image] "+content.Image.ContentType))
+		}
+		if content.Text != "" {
+			t.line(m.markdownLines(content.Text, indent, width))
+		}
+	}
+
+	switch msg.Status.Phase {
+	case v1.PhaseFailed:
+		t.line(indent + errorStyle.Render("Failed: "+messageError(msg).Error()))
+	case v1.PhaseCancelled:
+		t.line(indent + dimStyle.Render("Cancelled"))
+	case v1.PhasePending, v1.PhaseGenerating:
+		if !body.HasContent() {
+			t.line(indent + dimStyle.Render("…"))
+		}
+	}
+	t.line("")
+}
+
+// renderToolCall renders a call as a section that expands to its arguments, and the thread of the assistant it
+// called
+func (m *tui) renderToolCall(t *transcript, msg *v1.Message, call *v1.ToolCall, indent string, width int) {
+	var (
+		key      = callKey(msg, call.ID)
+		invoke   = m.state.invokes[key]
+		expanded = m.expanded[key]
+		line     = fmt.Sprintf("%s Called %s", arrow(expanded), call.Function.Name)
+	)
+	if invoke.Status.Phase != "" {
+		line += " " + dimStyle.Render(strings.ToLower(string(invoke.Status.Phase)))
+	}
+	m.sectionLine(t, key, indent, toolStyle.Render(line))
+	if !expanded {
+		return
+	}
+
+	t.line(m.markdownLines("```json\n"+call.Function.Arguments+"\n```", indent+"  ", width-2))
+	if invoke.Status.AssistantMessageName != "" {
+		t.line(indent + "  " + dimStyle.Render("Thread of "+call.Function.Name))
+		for i := range m.state.subThreads[key] {
+			m.renderMessage(t, &m.state.subThreads[key][i], indent+"  │ ", width-4)
+		}
+	}
+}
+
+// renderToolResult renders what a tool returned as a section that expands to it
+func (m *tui) renderToolResult(t *transcript, msg *v1.Message, indent string, width int) {
+	var (
+		expanded = m.expanded[msg.Name]
+		name     string
+		text     []string
+	)
+	if msg.Status.Message.ToolCall != nil {
+		name = msg.Status.Message.ToolCall.Function.Name
+	}
+	for _, content := range msg.Status.Message.Content {
+		if content.Text != "" {
+			text = append(text, content.Text)
+		}
+	}
+
+	result := strings.Join(text, "\n")
+	line := fmt.Sprintf("%s %s returned %d lines", arrow(expanded), name, strings.Count(result, "\n")+1)
+	m.sectionLine(t, msg.Name, indent, toolStyle.Render(line))
+	if expanded {
+		t.line(m.markdownLines("```\n"+result+"\n```", indent+"  ", width-2))
+	}
+	t.line("")
+}
+
+func (m *tui) sectionLine(t *transcript, key, indent, line string) {
+	t.sections = append(t.sections, section{key: key, line: t.lines})
+	if m.focus == paneTranscript && key == m.selectedKey {
+		line = selectedStyle.Render(line)
+	}
+	t.line(indent + line)
+}
+
+// markdownLines renders text as Markdown, with every line indented
+func (m *tui) markdownLines(text, indent string, width int) string {
+	cacheKey := fmt.Sprintf("%d\x00%s", width, text)
+	rendered, ok := m.rendered[cacheKey]
+	if !ok {
+		if len(m.rendered) > 1000 {
+			// Every version of a message streaming in is cached, so don't keep them all
+			m.rendered = map[string]string{}
+		}
+		rendered = text
+		if m.markdown[width] == nil {
+			// The style of the renderer has margins of two on both sides
+			m.markdown[width], _ = glamour.NewTermRenderer(glamour.WithStandardStyle(m.style),
+				glamour.WithWordWrap(max(width-4, 10)))
+		}
+		if renderer := m.markdown[width]; renderer != nil {
+			if out, err := renderer.Render(text); err == nil {
+				rendered = strings.Trim(out, "\n")
+			}
+		}
+		m.rendered[cacheKey] = rendered
+	}
+	if indent == "" {
+		return rendered
+	}
+	return indent + strings.ReplaceAll(rendered, "\n", "\n"+indent)
+}
+
+func arrow(expanded bool) string {
+	if expanded {
+		return "▾"
+	}
+	return "▸"
+}
+
+func (m *tui) View() string {
+	if m.width == 0 {
+		return "Loading…"
+	}
+
+	main := lipgloss.JoinVertical(lipgloss.Left,
+		m.header(),
+		m.paneStyle(paneTranscript).Width(m.viewport.Width).Render(m.viewport.View()),
+		m.paneStyle(paneInput).Render(m.input.View()),
+		m.footer(),
+	)
+	if m.hideSide {
+		return main
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.side(), main)
+}
+
+func (m *tui) header() string {
+	var (
+		thread = m.state.thread
+		parts  = []string{titleStyle.Render(orDefault(thread.Spec.AssistantName, "no assistant"))}
+	)
+	if thread.Name == "" {
+		parts = append(parts, "new thread")
+	} else {
+		parts = append(parts, orDefault(thread.Status.Description, thread.Name))
+	}
+	if thread.Status.Phase != "" {
+		parts = append(parts, strings.ToLower(string(thread.Status.Phase)))
+	}
+	if thread.Status.Usage != nil {
+		parts = append(parts, fmt.Sprintf("%d tokens", thread.Status.Usage.TotalTokens))
+	}
+	if m.editing != nil {
+		parts = append(parts, toolStyle.Render("editing "+m.editing.Name))
+	}
+	return truncate(" "+strings.Join(parts, dimStyle.Render(" • ")), m.viewport.Width+2)
+}
+
+func (m *tui) footer() string {
+	switch {
+	case m.isError && m.status != "":
+		return truncate(" "+errorStyle.Render(m.status), m.viewport.Width+2)
+	case m.status != "":
+		return truncate(" "+m.status, m.viewport.Width+2)
+	}
+	return truncate(" "+dimStyle.Render(keyHelp), m.viewport.Width+2)
+}
+
+// side renders the panes of the assistants and the threads of the current assistant
+func (m *tui) side() string {
+	var (
+		width            = sideWidth - 2
+		assistantsHeight = max((m.height-4)/3, 3)
+		threadsHeight    = max(m.height-4-assistantsHeight, 3)
+		assistants       []string
+		threads          = []string{"+ New thread"}
+		current          = -1
+	)
+
+	for _, assistant := range m.state.assistants {
+		assistants = append(assistants, assistant.Name)
+	}
+	for i, thread := range m.state.threads {
+		threads = append(threads, orDefault(thread.Status.Description, thread.Name))
+		if thread.Name == m.state.thread.Name {
+			current = i + 1
+		}
+	}
+	if m.state.thread.Name == "" {
+		current = 0
+	}
+	currentAssistant := -1
+	for i, assistant := range m.state.assistants {
+		if assistant.Name == m.state.thread.Spec.AssistantName {
+			currentAssistant = i
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.paneStyle(paneAssistants).Width(width).Height(assistantsHeight).
+			Render(m.list("Assistants", assistants, m.assistantCursor, currentAssistant, paneAssistants, width, assistantsHeight)),
+		m.paneStyle(paneThreads).Width(width).Height(threadsHeight).
+			Render(m.list("Threads", threads, m.threadCursor, current, paneThreads, width, threadsHeight)),
+	)
+}
+
+// list renders the items of a pane, scrolled to the cursor. The current item is marked, and the cursor is shown
+// when the pane has the focus.
+func (m *tui) list(title string, items []string, cursor, current int, p pane, width, height int) string {
+	lines := []string{titleStyle.Render(title)}
+	start := max(cursor-height+2, 0)
+	for i := start; i < len(items) && len(lines) < height; i++ {
+		marker := "  "
+		if i == current {
+			marker = "● "
+		}
+		line := truncate(marker+strings.ReplaceAll(items[i], "\n", " "), width)
+		if m.focus == p && i == cursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func truncate(s string, width int) string {
+	return reflowtruncate.StringWithTail(s, uint(max(width, 1)), "…")
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
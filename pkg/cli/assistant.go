@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type Assistant struct {
+	API
+	Namespace string `usage:"Set namespace" short:"n" env:"NAMESPACE" default:"local"`
+}
+
+func (a *Assistant) Customize(cmd *cobra.Command) {
+	cmd.Short = "Manage assistants"
+}
+
+func (a *Assistant) Run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/acorn-io/aml"
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// assistantFileExtensions are the files read from directories of assistant definitions
+var assistantFileExtensions = []string{".yaml", ".yml", ".json", ".aml", ".acorn"}
+
+// assistantFile is a file with an Assistant, or a list of them in items
+type assistantFile struct {
+	v1.Assistant `json:",inline"`
+	Items        []v1.Assistant `json:"items,omitempty"`
+}
+
+type AssistantApply struct {
+	assistant *Assistant
+
+	File []string `usage:"File or directory of assistant definitions in YAML, JSON or AML" short:"f" local:"true"`
+}
+
+func (a *AssistantApply) Customize(cmd *cobra.Command) {
+	cmd.Use = "apply [flags] -f FILE|DIR..."
+	cmd.Short = "Create or update assistants from files, or directories of files"
+	cmd.Args = cobra.NoArgs
+}
+
+func (a *AssistantApply) Run(cmd *cobra.Command, args []string) error {
+	if len(a.File) == 0 {
+		return fmt.Errorf("at least one file or directory is required")
+	}
+
+	assistants, err := readAssistantFiles(a.File)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.assistant.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, assistant := range assistants {
+		assistant.Namespace = a.assistant.Namespace
+
+		var existing v1.Assistant
+		err := client.Get(cmd.Context(), router.Key(assistant.Namespace, assistant.Name), &existing)
+		if apierrors.IsNotFound(err) {
+			if err := client.Create(cmd.Context(), &assistant); err != nil {
+				return fmt.Errorf("creating assistant %s: %w", assistant.Name, err)
+			}
+			fmt.Printf("assistant/%s created\n", assistant.Name)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		updated := existing.DeepCopy()
+		updated.Spec = assistant.Spec
+		updated.Labels = mergeMap(updated.Labels, assistant.Labels)
+		updated.Annotations = mergeMap(updated.Annotations, assistant.Annotations)
+		if equality.Semantic.DeepEqual(&existing, updated) {
+			fmt.Printf("assistant/%s unchanged\n", assistant.Name)
+			continue
+		}
+
+		if err := client.Update(cmd.Context(), updated); err != nil {
+			return fmt.Errorf("updating assistant %s: %w", assistant.Name, err)
+		}
+		fmt.Printf("assistant/%s configured\n", assistant.Name)
+	}
+
+	return nil
+}
+
+// readAssistantFiles reads the assistants defined in paths. The files in directories, and the directories below
+// them, are read in the order of their names.
+func readAssistantFiles(paths []string) (result []v1.Assistant, _ error) {
+	var files []string
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && slices.Contains(assistantFileExtensions, strings.ToLower(filepath.Ext(path))) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	names := map[string]string{}
+	for _, file := range files {
+		assistants, err := readAssistantFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		for _, assistant := range assistants {
+			if other, ok := names[assistant.Name]; ok {
+				return nil, fmt.Errorf("assistant %s is defined in %s and %s", assistant.Name, other, file)
+			}
+			names[assistant.Name] = file
+		}
+		result = append(result, assistants...)
+	}
+
+	return result, nil
+}
+
+func readAssistantFile(path string) ([]v1.Assistant, error) {
+	var file assistantFile
+	if err := aml.UnmarshalFile(path, &file); err != nil {
+		return nil, err
+	}
+
+	assistants := file.Items
+	if len(assistants) == 0 {
+		assistants = []v1.Assistant{file.Assistant}
+	}
+
+	result := make([]v1.Assistant, 0, len(assistants))
+	for _, assistant := range assistants {
+		switch {
+		case assistant.Kind != "" && assistant.Kind != "Assistant":
+			return nil, fmt.Errorf("kind %s is not Assistant", assistant.Kind)
+		case assistant.APIVersion != "" && assistant.APIVersion != v1.SchemeGroupVersion.String():
+			return nil, fmt.Errorf("apiVersion %s is not %s", assistant.APIVersion, v1.SchemeGroupVersion)
+		case assistant.Name == "":
+			return nil, fmt.Errorf("metadata.name is required")
+		}
+		// Only the definition is applied
+		result = append(result, v1.Assistant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        assistant.Name,
+				Labels:      assistant.Labels,
+				Annotations: assistant.Annotations,
+			},
+			Spec: assistant.Spec,
+		})
+	}
+	return result, nil
+}
+
+// mergeMap returns existing with the keys of values set
+func mergeMap(existing, values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return existing
+	}
+	result := make(map[string]string, len(existing)+len(values))
+	for k, v := range existing {
+		result[k] = v
+	}
+	for k, v := range values {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AssistantDelete struct {
+	assistant *Assistant
+
+	File []string `usage:"Delete the assistants defined in a file or directory" short:"f" local:"true"`
+}
+
+func (a *AssistantDelete) Customize(cmd *cobra.Command) {
+	cmd.Use = "delete [flags] [NAME...]"
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = "Delete assistants by name, or the ones defined in files or directories"
+}
+
+func (a *AssistantDelete) Run(cmd *cobra.Command, args []string) error {
+	names := args
+	if len(a.File) > 0 {
+		assistants, err := readAssistantFiles(a.File)
+		if err != nil {
+			return err
+		}
+		for _, assistant := range assistants {
+			names = append(names, assistant.Name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("at least one name, file or directory is required")
+	}
+
+	client, err := a.assistant.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		err := client.Delete(cmd.Context(), &v1.Assistant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: a.assistant.Namespace,
+			},
+		})
+		if apierrors.IsNotFound(err) {
+			fmt.Printf("assistant/%s not found\n", name)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("deleting assistant %s: %w", name, err)
+		}
+		fmt.Printf("assistant/%s deleted\n", name)
+	}
+
+	return nil
+}
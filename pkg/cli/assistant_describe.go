@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/spf13/cobra"
+)
+
+type AssistantDescribe struct {
+	assistant *Assistant
+}
+
+func (a *AssistantDescribe) Customize(cmd *cobra.Command) {
+	cmd.Use = "describe NAME"
+	cmd.Short = "Describe an assistant, its tools and its usage"
+	cmd.Args = cobra.ExactArgs(1)
+}
+
+func (a *AssistantDescribe) Run(cmd *cobra.Command, args []string) error {
+	client, err := a.assistant.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var assistant v1.Assistant
+	if err := client.Get(cmd.Context(), router.Key(a.assistant.Namespace, args[0]), &assistant); err != nil {
+		return err
+	}
+
+	var (
+		spec = assistant.Spec
+		w    = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	)
+
+	_, _ = fmt.Fprintf(w, "Name:\t%s\n", assistant.Name)
+	_, _ = fmt.Fprintf(w, "Namespace:\t%s\n", assistant.Namespace)
+	_, _ = fmt.Fprintf(w, "Created:\t%s\n", assistant.CreationTimestamp.UTC().Format("2006-01-02 15:04:05 MST"))
+	if spec.Name != "" {
+		_, _ = fmt.Fprintf(w, "Display Name:\t%s\n", spec.Name)
+	}
+	_, _ = fmt.Fprintf(w, "Description:\t%s\n", orNone(spec.Description))
+	_, _ = fmt.Fprintf(w, "Provider:\t%s\n", orNone(spec.Provider))
+	_, _ = fmt.Fprintf(w, "Model:\t%s\n", orNone(spec.Model))
+	_, _ = fmt.Fprintf(w, "Vision:\t%t\n", spec.Vision)
+	if spec.MaxTokens > 0 {
+		_, _ = fmt.Fprintf(w, "Max Tokens:\t%d\n", spec.MaxTokens)
+	}
+	_, _ = fmt.Fprintf(w, "JSON Response:\t%t\n", spec.JSONResponse || spec.ResponseSchema != nil)
+	if spec.Cache != nil {
+		_, _ = fmt.Fprintf(w, "Cache:\t%t\n", *spec.Cache)
+	}
+	if spec.Truncation != nil {
+		truncation := string(spec.Truncation.Strategy)
+		if truncation == "" {
+			truncation = string(v1.TruncationStrategyDropOldest)
+		}
+		if spec.Truncation.Strategy == v1.TruncationStrategyKeepLast {
+			truncation += fmt.Sprintf(" %d", spec.Truncation.LastMessages)
+		}
+		if spec.Truncation.ContextWindow > 0 {
+			truncation += fmt.Sprintf(", context window %d", spec.Truncation.ContextWindow)
+		}
+		_, _ = fmt.Fprintf(w, "Truncation:\t%s\n", truncation)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if spec.Instructions != "" {
+		fmt.Printf("\nInstructions:\n%s\n", indent(spec.Instructions))
+	}
+
+	if len(spec.Tools) > 0 {
+		fmt.Printf("\nTools:\n")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(w, "  NAME\tCALLED BY\tPARAMETERS\tDESCRIPTION")
+		for _, tool := range spec.Tools {
+			calledBy := "runtime"
+			switch {
+			case tool.Function.Client:
+				calledBy = "client"
+			case tool.Function.Domain != "":
+				calledBy = tool.Function.Domain
+			}
+			params := "-"
+			if tool.Function.Parameters != nil {
+				var names []string
+				for name := range tool.Function.Parameters.Properties {
+					names = append(names, name)
+				}
+				if len(names) > 0 {
+					slices.Sort(names)
+					params = strings.Join(names, ",")
+				}
+			}
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", tool.Function.Name, calledBy, params,
+				firstLine(tool.Function.Description))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if spec.ResponseSchema != nil {
+		data, err := json.MarshalIndent(spec.ResponseSchema, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\nResponse Schema:\n%s\n", indent(string(data)))
+	}
+
+	fmt.Printf("\nUsage:\n")
+	usage := assistant.Status.Usage
+	if usage == nil {
+		usage = &v1.UsageTotals{}
+	}
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintf(w, "  Completions:\t%d (%d cached)\n", usage.Completions, usage.CachedCompletions)
+	_, _ = fmt.Fprintf(w, "  Prompt Tokens:\t%d\n", usage.PromptTokens)
+	_, _ = fmt.Fprintf(w, "  Completion Tokens:\t%d\n", usage.CompletionTokens)
+	_, _ = fmt.Fprintf(w, "  Total Tokens:\t%d\n", usage.TotalTokens)
+	_, _ = fmt.Fprintf(w, "  Cached Tokens:\t%d\n", usage.CachedTokens)
+	_, _ = fmt.Fprintf(w, "  Cost:\t$%.4f\n", usage.Cost)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(assistant.Status.Conditions) > 0 {
+		fmt.Printf("\nConditions:\n")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(w, "  TYPE\tSTATUS\tMESSAGE")
+		for _, cond := range assistant.Status.Conditions {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", cond.Type, cond.Status, cond.Message)
+		}
+		return w.Flush()
+	}
+
+	return nil
+}
+
+func indent(s string) string {
+	return "  " + strings.ReplaceAll(strings.TrimRight(s, "\n"), "\n", "\n  ")
+}
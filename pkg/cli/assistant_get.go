@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+type AssistantGet struct {
+	assistant *Assistant
+
+	Output string `usage:"Format of the assistant, yaml or json" short:"o" default:"yaml" local:"true"`
+}
+
+func (a *AssistantGet) Customize(cmd *cobra.Command) {
+	cmd.Use = "get [flags] NAME"
+	cmd.Short = "Print an assistant in a form that can be changed and applied again"
+	cmd.Args = cobra.ExactArgs(1)
+}
+
+func (a *AssistantGet) Run(cmd *cobra.Command, args []string) error {
+	if a.Output != "yaml" && a.Output != "json" {
+		return fmt.Errorf("invalid output %s, must be yaml or json", a.Output)
+	}
+
+	client, err := a.assistant.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var assistant v1.Assistant
+	if err := client.Get(cmd.Context(), router.Key(a.assistant.Namespace, args[0]), &assistant); err != nil {
+		return err
+	}
+
+	assistant.APIVersion = v1.SchemeGroupVersion.String()
+	assistant.Kind = "Assistant"
+	cleanMeta(&assistant.ObjectMeta)
+
+	data, err := json.MarshalIndent(assistant, "", "  ")
+	if err != nil {
+		return err
+	}
+	if a.Output == "yaml" {
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return err
+		}
+	} else {
+		data = append(data, '\n')
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
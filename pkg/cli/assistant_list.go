@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/spf13/cobra"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type AssistantList struct {
+	assistant *Assistant
+}
+
+func (a *AssistantList) Customize(cmd *cobra.Command) {
+	cmd.Use = "list"
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = "List assistants"
+	cmd.Args = cobra.NoArgs
+}
+
+func (a *AssistantList) Run(cmd *cobra.Command, args []string) error {
+	client, err := a.assistant.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var assistants v1.AssistantList
+	if err := client.List(cmd.Context(), &assistants, &kclient.ListOptions{
+		Namespace: a.assistant.Namespace,
+	}); err != nil {
+		return err
+	}
+
+	slices.SortFunc(assistants.Items, func(a, b v1.Assistant) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tMODEL\tTOOLS\tCOMPLETIONS\tDESCRIPTION")
+	for _, assistant := range assistants.Items {
+		var tools []string
+		for _, tool := range assistant.Spec.Tools {
+			tools = append(tools, tool.Function.Name)
+		}
+		completions := 0
+		if assistant.Status.Usage != nil {
+			completions = assistant.Status.Usage.Completions
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", assistant.Name, orNone(assistant.Spec.Model),
+			orNone(strings.Join(tools, ",")), completions, firstLine(assistant.Spec.Description))
+	}
+	return w.Flush()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// firstLine returns the first line of s, shortened to fit a table
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(s, "\n")
+	if len(s) > 60 {
+		return s[:57] + "..."
+	}
+	return s
+}
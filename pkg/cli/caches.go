@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/spf13/cobra"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Caches struct {
+	API
+	Namespace string `usage:"Set namespace" short:"n" env:"NAMESPACE" default:"local"`
+}
+
+type cacheStats struct {
+	entries     int
+	size        int64
+	hits        int
+	completions int
+	cached      int
+}
+
+func (c *Caches) Run(cmd *cobra.Command, args []string) error {
+	client, err := c.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var (
+		caches     v1.CacheList
+		assistants v1.AssistantList
+		stats      = map[string]*cacheStats{}
+	)
+
+	if err := client.List(cmd.Context(), &caches, &kclient.ListOptions{
+		Namespace: c.Namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := client.List(cmd.Context(), &assistants, &kclient.ListOptions{
+		Namespace: c.Namespace,
+	}); err != nil {
+		return err
+	}
+
+	get := func(name string) *cacheStats {
+		if _, ok := stats[name]; !ok {
+			stats[name] = &cacheStats{}
+		}
+		return stats[name]
+	}
+
+	for _, cache := range caches.Items {
+		s := get(cache.Labels[v1.CacheAssistantLabel])
+		s.entries++
+		s.size += int64(len(cache.Content))
+		s.hits += cache.Status.HitCount
+	}
+
+	for _, assistant := range assistants.Items {
+		s := get(assistant.Name)
+		if assistant.Status.Usage != nil {
+			s.completions += assistant.Status.Usage.Completions
+			s.cached += assistant.Status.Usage.CachedCompletions
+		}
+	}
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ASSISTANT\tENTRIES\tSIZE\tHITS\tCOMPLETIONS\tCACHED\tHIT RATIO")
+	for _, name := range names {
+		s := stats[name]
+		ratio := "-"
+		if s.completions > 0 {
+			ratio = fmt.Sprintf("%.1f%%", float64(s.cached)*100/float64(s.completions))
+		}
+		if name == "" {
+			name = "<none>"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%d\t%s\n", name, s.entries, humanSize(s.size), s.hits, s.completions, s.cached, ratio)
+	}
+	return w.Flush()
+}
+
+func humanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const cacheFileSuffix = ".json.gz"
+
+// cacheFile is the exported form of a Cache. The responses are stored decoded so that the files can be
+// inspected and edited, and are compressed again on import.
+type cacheFile struct {
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Usage     *v1.Usage         `json:"usage,omitempty"`
+	Responses json.RawMessage   `json:"responses"`
+}
+
+type CachesExport struct {
+	caches *Caches
+
+	Thread string `usage:"Only export responses generated for this thread" local:"true"`
+}
+
+func (c *CachesExport) Customize(cmd *cobra.Command) {
+	cmd.Use = "export [flags] DIR"
+	cmd.Short = "Export cached responses to a directory of gzip JSON files"
+	cmd.Args = cobra.ExactArgs(1)
+}
+
+func (c *CachesExport) Run(cmd *cobra.Command, args []string) error {
+	client, err := c.caches.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	opts := &kclient.ListOptions{
+		Namespace: c.caches.Namespace,
+	}
+	if c.Thread != "" {
+		opts.LabelSelector = labels.SelectorFromSet(labels.Set{
+			v1.CacheThreadLabel: c.Thread,
+		})
+	}
+
+	var caches v1.CacheList
+	if err := client.List(cmd.Context(), &caches, opts); err != nil {
+		return err
+	}
+
+	dir := args[0]
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, cache := range caches.Items {
+		if err := writeCacheFile(filepath.Join(dir, cache.Name+cacheFileSuffix), cache); err != nil {
+			return fmt.Errorf("exporting cache %s: %w", cache.Name, err)
+		}
+	}
+
+	fmt.Printf("Exported %d cached responses to %s\n", len(caches.Items), dir)
+	return nil
+}
+
+func writeCacheFile(path string, cache v1.Cache) error {
+	gz, err := gzip.NewReader(bytes.NewReader(cache.Content))
+	if err != nil {
+		return err
+	}
+	responses, err := io.ReadAll(gz)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	out := gzip.NewWriter(f)
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(cacheFile{
+		Name:      cache.Name,
+		Labels:    cache.Labels,
+		Usage:     cache.Usage,
+		Responses: bytes.TrimSpace(responses),
+	}); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CachesImport struct {
+	caches *Caches
+
+	Replace bool `usage:"Replace cached responses that already exist" local:"true"`
+}
+
+func (c *CachesImport) Customize(cmd *cobra.Command) {
+	cmd.Use = "import [flags] DIR"
+	cmd.Short = "Import cached responses from a directory created by export"
+	cmd.Long = "Import cached responses from a directory created by export. Imported responses are replayed even " +
+		"if no model provider is configured, for assistants with cache: true that resolve to the same model, " +
+		"as the key of a cached response covers the whole request including the model."
+	cmd.Args = cobra.ExactArgs(1)
+}
+
+func (c *CachesImport) Run(cmd *cobra.Command, args []string) error {
+	client, err := c.caches.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(args[0], "*"+cacheFileSuffix))
+	if err != nil {
+		return err
+	}
+
+	var created, skipped int
+	for _, file := range files {
+		cache, err := readCacheFile(file)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", file, err)
+		}
+		cache.Namespace = c.caches.Namespace
+
+		err = client.Create(cmd.Context(), cache)
+		if apierrors.IsAlreadyExists(err) && c.Replace {
+			var existing v1.Cache
+			if err := client.Get(cmd.Context(), router.Key(cache.Namespace, cache.Name), &existing); err != nil {
+				return err
+			}
+			existing.Labels = cache.Labels
+			existing.Content = cache.Content
+			existing.Usage = cache.Usage
+			err = client.Update(cmd.Context(), &existing)
+		} else if apierrors.IsAlreadyExists(err) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("importing %s: %w", file, err)
+		}
+		created++
+	}
+
+	fmt.Printf("Imported %d cached responses, skipped %d that already exist\n", created, skipped)
+	return nil
+}
+
+func readCacheFile(path string) (*v1.Cache, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var in cacheFile
+	if err := json.NewDecoder(gz).Decode(&in); err != nil {
+		return nil, err
+	}
+	if in.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+
+	buf := &bytes.Buffer{}
+	out := gzip.NewWriter(buf)
+	if _, err := out.Write(in.Responses); err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+
+	return &v1.Cache{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   in.Name,
+			Labels: in.Labels,
+		},
+		Content: buf.Bytes(),
+		Usage:   in.Usage,
+	}, nil
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/acorn-io/assistant-runtime/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/restconfig"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// API is the connection to the assistant runtime API shared by commands that are clients of the server
+type API struct {
+	URL   string `usage:"URL of assistant runtime API" default:"http://localhost:8080"`
+	Token string `usage:"Bearer token to talk to assistant runtime API"`
+}
+
+func (a API) Client(ctx context.Context) (kclient.WithWatch, error) {
+	restConfig, err := restconfig.FromURLTokenAndScheme(a.URL, a.Token, scheme.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := restconfig.WaitFor(ctx, restConfig); err != nil {
+		return nil, err
+	}
+
+	return kclient.NewWithWatch(restConfig, kclient.Options{
+		Scheme: scheme.Scheme,
+	})
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/acorn-io/assistant-runtime/pkg/fakellm"
+	"github.com/spf13/cobra"
+)
+
+type FakeLLM struct {
+	Port   int    `usage:"Port to listen on" default:"8089"`
+	Script string `usage:"YAML, JSON or AML file of rules to respond with, the last message is echoed back if no rule matches"`
+}
+
+func (f *FakeLLM) Run(cmd *cobra.Command, args []string) error {
+	script, err := fakellm.LoadScript(f.Script)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", f.Port),
+		Handler: fakellm.NewServer(script),
+	}
+
+	go func() {
+		<-cmd.Context().Done()
+		_ = server.Close()
+	}()
+
+	slog.Info("fake LLM listening", "address", server.Addr, "rules", len(script.Rules))
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
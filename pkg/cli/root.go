@@ -14,10 +14,30 @@ func (a *AssistantRuntime) PersistentPre(cmd *cobra.Command, args []string) erro
 }
 
 func New() *cobra.Command {
+	assistant := &Assistant{}
+	caches := &Caches{}
+	thread := &Thread{}
 	return cmd.Command(&AssistantRuntime{},
 		&Controller{},
 		&Server{},
-		&Chat{})
+		&Chat{},
+		&FakeLLM{},
+		cmd.Command(assistant,
+			&AssistantApply{assistant: assistant},
+			&AssistantList{assistant: assistant},
+			&AssistantGet{assistant: assistant},
+			&AssistantDescribe{assistant: assistant},
+			&AssistantDelete{assistant: assistant}),
+		cmd.Command(caches,
+			&CachesExport{caches: caches},
+			&CachesImport{caches: caches}),
+		cmd.Command(thread,
+			&ThreadList{thread: thread},
+			&ThreadShow{thread: thread},
+			&ThreadTree{thread: thread},
+			&ThreadTail{thread: thread},
+			&ThreadExport{thread: thread},
+			&ThreadImport{thread: thread}))
 }
 
 func (a *AssistantRuntime) Run(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type Thread struct {
+	API
+	Namespace string `usage:"Set namespace" short:"n" env:"NAMESPACE" default:"local"`
+}
+
+func (t *Thread) Customize(cmd *cobra.Command) {
+	cmd.Short = "Manage threads"
+}
+
+func (t *Thread) Run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
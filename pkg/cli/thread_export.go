@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const threadExportVersion = 1
+
+// imageURLPath matches the path of an image served by the API in the URL of an image or the text of a message
+var imageURLPath = regexp.MustCompile(`/namespaces/([^/]+)/images/([^/"]+)/serve`)
+
+// threadExport is the exported form of a thread. It has all the messages of the thread, including the ones on
+// other branches, the tool calls they made, the threads of the assistants they called, and the images they
+// reference.
+type threadExport struct {
+	Version     int             `json:"version,omitempty"`
+	Thread      v1.Thread       `json:"thread"`
+	Messages    []v1.Message    `json:"messages"`
+	InvokeTools []v1.InvokeTool `json:"invokeTools,omitempty"`
+	Threads     []threadExport  `json:"threads,omitempty"`
+	Images      []v1.Image      `json:"images,omitempty"`
+}
+
+type ThreadExport struct {
+	thread *Thread
+
+	Output string `usage:"Format of the export, json or markdown" short:"o" default:"json" local:"true"`
+}
+
+func (t *ThreadExport) Customize(cmd *cobra.Command) {
+	cmd.Use = "export [flags] THREAD [FILE]"
+	cmd.Short = "Export a thread as a JSON document or a Markdown transcript, to stdout if no file is given"
+	cmd.Args = cobra.RangeArgs(1, 2)
+}
+
+func (t *ThreadExport) Run(cmd *cobra.Command, args []string) error {
+	if t.Output != "json" && t.Output != "markdown" {
+		return fmt.Errorf("invalid output %s, must be json or markdown", t.Output)
+	}
+
+	client, err := t.thread.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	export, err := exportThread(cmd.Context(), client, t.thread.Namespace, args[0])
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if len(args) > 1 {
+		f, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if t.Output == "markdown" {
+		err = writeTranscript(out, export, 1)
+	} else {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(export)
+	}
+	if err != nil {
+		return err
+	}
+
+	if f, ok := out.(*os.File); ok && f != os.Stdout {
+		return f.Close()
+	}
+	return nil
+}
+
+// threadObjects are the objects of a namespace that threads are exported from
+type threadObjects struct {
+	messages v1.MessageList
+	invokes  v1.InvokeToolList
+	threads  v1.ThreadList
+}
+
+func exportThread(ctx context.Context, c kclient.Client, namespace, name string) (*threadExport, error) {
+	var (
+		thread  v1.Thread
+		objects threadObjects
+		opts    = &kclient.ListOptions{Namespace: namespace}
+	)
+
+	if err := c.Get(ctx, router.Key(namespace, name), &thread); err != nil {
+		return nil, err
+	}
+	if err := c.List(ctx, &objects.messages, opts); err != nil {
+		return nil, err
+	}
+	if err := c.List(ctx, &objects.invokes, opts); err != nil {
+		return nil, err
+	}
+	if err := c.List(ctx, &objects.threads, opts); err != nil {
+		return nil, err
+	}
+
+	export := objects.export(thread)
+	export.Version = threadExportVersion
+
+	images, err := referencedImages(ctx, c, export)
+	if err != nil {
+		return nil, err
+	}
+	export.Images = images
+
+	return export, nil
+}
+
+func (o *threadObjects) export(thread v1.Thread) *threadExport {
+	result := &threadExport{
+		Thread: thread,
+	}
+	cleanMeta(&result.Thread.ObjectMeta)
+
+	for _, msg := range o.messages.Items {
+		if msg.Status.ThreadName == thread.Name {
+			cleanMeta(&msg.ObjectMeta)
+			result.Messages = append(result.Messages, msg)
+		}
+	}
+	sortByCreation(result.Messages)
+
+	for _, invoke := range o.invokes.Items {
+		if invoke.Spec.ThreadName == thread.Name {
+			cleanMeta(&invoke.ObjectMeta)
+			result.InvokeTools = append(result.InvokeTools, invoke)
+		}
+	}
+	sortByCreation(result.InvokeTools)
+
+	for _, child := range o.threads.Items {
+		if child.Spec.ParentThreadName == thread.Name {
+			result.Threads = append(result.Threads, *o.export(child))
+		}
+	}
+	slices.SortFunc(result.Threads, func(a, b threadExport) int {
+		return a.Thread.CreationTimestamp.Compare(b.Thread.CreationTimestamp.Time)
+	})
+
+	return result
+}
+
+// cleanMeta removes the fields that only mean something to the server the object is exported from
+func cleanMeta(obj *metav1.ObjectMeta) {
+	obj.UID = ""
+	obj.ResourceVersion = ""
+	obj.ManagedFields = nil
+	obj.OwnerReferences = nil
+}
+
+// referencedImages returns the images that messages of the export and its threads reference
+func referencedImages(ctx context.Context, c kclient.Client, export *threadExport) (result []v1.Image, _ error) {
+	var (
+		namespace = export.Thread.Namespace
+		names     []string
+	)
+
+	export.walk(func(e *threadExport) {
+		for _, msg := range e.Messages {
+			names = append(names, msg.Spec.FileNames...)
+			for _, content := range append(msg.Status.Message.Content, msg.Spec.Input.Content...) {
+				text := content.Text
+				if content.Image != nil {
+					text += content.Image.URL
+				}
+				for _, match := range imageURLPath.FindAllStringSubmatch(text, -1) {
+					if match[1] == namespace {
+						names = append(names, match[2])
+					}
+				}
+			}
+		}
+	})
+
+	slices.Sort(names)
+	for _, name := range slices.Compact(names) {
+		var image v1.Image
+		if err := c.Get(ctx, router.Key(namespace, name), &image); apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		cleanMeta(&image.ObjectMeta)
+		result = append(result, image)
+	}
+
+	return result, nil
+}
+
+// walk calls f with the export and the exports of all the threads below it
+func (e *threadExport) walk(f func(*threadExport)) {
+	f(e)
+	for i := range e.Threads {
+		e.Threads[i].walk(f)
+	}
+}
+
+// activeMessages returns the messages on the active branch of the exported thread
+func (e *threadExport) activeMessages() (result []v1.Message) {
+	byName := map[string]v1.Message{}
+	for _, msg := range e.Messages {
+		byName[msg.Name] = msg
+	}
+
+	next := e.Thread.Spec.StartMessageName
+	for next != "" {
+		msg, ok := byName[next]
+		if !ok {
+			break
+		}
+		result = append(result, msg)
+		next = msg.Status.NextMessageName
+	}
+	return result
+}
+
+// writeTranscript writes the active branch of the exported thread as Markdown, followed by the threads of the
+// assistants it called
+func writeTranscript(w io.Writer, export *threadExport, level int) error {
+	var (
+		buf     = &strings.Builder{}
+		heading = strings.Repeat("#", level)
+		title   = export.Thread.Status.Description
+	)
+
+	if title == "" {
+		title = export.Thread.Name
+	}
+	fmt.Fprintf(buf, "%s %s\n\n", heading, title)
+	fmt.Fprintf(buf, "- Thread: `%s`\n", export.Thread.Name)
+	fmt.Fprintf(buf, "- Assistant: `%s`\n", export.Thread.Spec.AssistantName)
+	if export.Thread.Spec.ParentThreadName != "" {
+		fmt.Fprintf(buf, "- Called from thread: `%s`\n", export.Thread.Spec.ParentThreadName)
+	}
+	fmt.Fprintf(buf, "- Created: %s\n\n", export.Thread.CreationTimestamp.UTC().Format("2006-01-02 15:04:05 MST"))
+
+	for _, msg := range export.activeMessages() {
+		body := msg.Status.Message
+		switch body.Role {
+		case v1.RoleTypeUser:
+			fmt.Fprintf(buf, "%s# User\n\n", heading)
+		case v1.RoleTypeAssistant:
+			fmt.Fprintf(buf, "%s# Assistant\n\n", heading)
+		case v1.RoleTypeTool:
+			name := ""
+			if body.ToolCall != nil {
+				name = body.ToolCall.Function.Name
+			}
+			fmt.Fprintf(buf, "%s# Tool `%s`\n\n", heading, name)
+		default:
+			continue
+		}
+
+		for _, content := range body.Content {
+			switch {
+			case content.ToolCall != nil:
+				fmt.Fprintf(buf, "Called `%s` with\n\n```json\n%s\n```\n\n", content.ToolCall.Function.Name,
+					content.ToolCall.Function.Arguments)
+			case content.Image != nil && content.Image.URL != "":
+				fmt.Fprintf(buf, "![image](%s)\n\n", content.Image.URL)
+			case content.Image != nil:
+				fmt.Fprintf(buf, "*%s image*\n\n", content.Image.ContentType)
+			}
+			if content.Text == "" {
+				continue
+			}
+			if body.Role == v1.RoleTypeTool {
+				fmt.Fprintf(buf, "```\n%s\n```\n\n", content.Text)
+			} else {
+				fmt.Fprintf(buf, "%s\n\n", content.Text)
+			}
+		}
+
+		if msg.Status.Phase == v1.PhaseFailed {
+			fmt.Fprintf(buf, "*Failed*\n\n")
+		}
+	}
+
+	if _, err := io.WriteString(w, strings.TrimRight(buf.String(), "\n")+"\n"); err != nil {
+		return err
+	}
+
+	for i := range export.Threads {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+		if err := writeTranscript(w, &export.Threads[i], level+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortByCreation sorts objects from the oldest to the newest
+func sortByCreation[T any, PT interface {
+	*T
+	kclient.Object
+}](objs []T) {
+	slices.SortStableFunc(objs, func(a, b T) int {
+		ta, tb := PT(&a).GetCreationTimestamp(), PT(&b).GetCreationTimestamp()
+		if c := ta.Time.Compare(tb.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(PT(&a).GetName(), PT(&b).GetName())
+	})
+}
@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/server/registry/apigroups/assistant/threads"
+	"github.com/acorn-io/baaah/pkg/name"
+	"github.com/acorn-io/baaah/pkg/watcher"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type ThreadImport struct {
+	thread *Thread
+
+	Assistant string `usage:"Assistant of the imported thread instead of the exported one" local:"true"`
+}
+
+func (t *ThreadImport) Customize(cmd *cobra.Command) {
+	cmd.Use = "import [flags] FILE"
+	cmd.Short = "Import a thread from a JSON document created by export, or from stdin if FILE is -"
+	cmd.Long = "Import a thread from a JSON document created by export. The thread, its messages and the threads " +
+		"of the assistants it called get new names. Responses and tool calls are imported as they were exported, " +
+		"they are not generated or invoked again."
+	cmd.Args = cobra.ExactArgs(1)
+}
+
+func (t *ThreadImport) Run(cmd *cobra.Command, args []string) error {
+	client, err := t.thread.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	in := io.Reader(os.Stdin)
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var export threadExport
+	if err := json.NewDecoder(in).Decode(&export); err != nil {
+		return fmt.Errorf("reading %s: %w", args[0], err)
+	}
+	if export.Version != threadExportVersion {
+		return fmt.Errorf("unsupported export version %d, expected %d", export.Version, threadExportVersion)
+	}
+	if t.Assistant != "" {
+		export.Thread.Spec.AssistantName = t.Assistant
+	}
+
+	i := &threadImporter{
+		c:         client,
+		namespace: t.thread.Namespace,
+		from:      export.Thread.Namespace,
+	}
+
+	if err := i.images(cmd.Context(), export.Images); err != nil {
+		return err
+	}
+
+	thread, err := i.thread(cmd.Context(), &export, "")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported thread %s with %d messages and %d called threads\n", thread.Name, i.messages, i.threads)
+	return nil
+}
+
+type threadImporter struct {
+	c         kclient.WithWatch
+	namespace string
+	// from is the namespace the thread was exported from
+	from     string
+	messages int
+	// threads is the number of threads of called assistants
+	threads int
+}
+
+// images creates the exported images. Images are named by their content, so the names are kept and existing
+// images are used.
+func (i *threadImporter) images(ctx context.Context, images []v1.Image) error {
+	for _, image := range images {
+		err := i.c.Create(ctx, &v1.Image{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      image.Name,
+				Namespace: i.namespace,
+			},
+			Spec: image.Spec,
+		})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("importing image %s: %w", image.Name, err)
+		}
+	}
+	return nil
+}
+
+// thread creates the exported thread and its messages with new names, followed by the threads of the assistants
+// it called
+func (i *threadImporter) thread(ctx context.Context, export *threadExport, parentThreadName string) (*v1.Thread, error) {
+	var (
+		names  = map[string]string{}
+		thread = &v1.Thread{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: name.SafeConcatName(export.Thread.Spec.AssistantName, ""),
+				Namespace:    i.namespace,
+			},
+			Spec: v1.ThreadSpec{
+				ParentThreadName: parentThreadName,
+				AssistantName:    export.Thread.Spec.AssistantName,
+			},
+		}
+		active = export.activeMessages()
+	)
+
+	msgs, err := export.messagesByParent()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, msg := range msgs {
+		msgCopy := i.message(&msg, names[msg.Spec.ParentMessageName])
+		if err := i.c.Create(ctx, msgCopy); err != nil {
+			return nil, fmt.Errorf("importing message %s: %w", msg.Name, err)
+		}
+		names[msg.Name] = msgCopy.Name
+		i.messages++
+
+		if msg.Name == export.Thread.Spec.StartMessageName {
+			// The start message is created first, like a new thread from a client
+			thread.Spec.StartMessageName = msgCopy.Name
+			if err := i.c.Create(ctx, thread); err != nil {
+				return nil, fmt.Errorf("importing thread %s: %w", export.Thread.Name, err)
+			}
+		}
+	}
+
+	if thread.Name == "" {
+		return nil, fmt.Errorf("thread %s has no start message to import", export.Thread.Name)
+	}
+
+	// Keep the branch that was active when the thread was exported, once the messages are added to the thread.
+	// A message added after that would start the active branch instead.
+	for _, msg := range msgs {
+		_, err := watcher.New[*v1.Message](i.c).ByName(ctx, i.namespace, names[msg.Name], func(msg *v1.Message) (bool, error) {
+			return msg.Status.ThreadName != "", nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("waiting for message %s: %w", msg.Name, err)
+		}
+	}
+	for j := len(active) - 1; j >= 0; j-- {
+		if activeName, ok := names[active[j].Name]; ok {
+			patch := kclient.MergeFrom(thread.DeepCopy())
+			thread.Spec.ActiveMessageName = activeName
+			if err := i.c.Patch(ctx, thread, patch); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	for j := range export.Threads {
+		if _, err := i.thread(ctx, &export.Threads[j], thread.Name); err != nil {
+			return nil, err
+		}
+		i.threads++
+	}
+
+	return thread, nil
+}
+
+// message returns a new message with the content of msg. More messages are said to follow every message, so no
+// responses are generated and no tools are invoked for the imported messages.
+func (i *threadImporter) message(msg *v1.Message, parentName string) *v1.Message {
+	msgCopy := threads.CopyMessage(msg, parentName, true)
+	msgCopy.Namespace = i.namespace
+	msgCopy.Annotations = msg.Annotations
+	msgCopy.Spec.Input.Content = i.content(msg.Status.Message.Content)
+	return msgCopy
+}
+
+// messagesByParent returns the messages of the exported thread that were generated, every message after its
+// parent, starting from the start message. Messages that can't be reached from the start message are an error,
+// they would be lost by the import.
+func (e *threadExport) messagesByParent() (result []v1.Message, _ error) {
+	var (
+		children = map[string][]v1.Message{}
+		start    *v1.Message
+		placed   = map[string]bool{}
+	)
+	for j, msg := range e.Messages {
+		if msg.Name == e.Thread.Spec.StartMessageName {
+			start = &e.Messages[j]
+			continue
+		}
+		children[msg.Spec.ParentMessageName] = append(children[msg.Spec.ParentMessageName], msg)
+	}
+	if start == nil {
+		return nil, fmt.Errorf("thread %s has no start message to import", e.Thread.Name)
+	}
+
+	var walk func(msg v1.Message, generated bool)
+	walk = func(msg v1.Message, generated bool) {
+		placed[msg.Name] = true
+		// A message that was never generated is skipped, so is everything below it
+		generated = generated && msg.Status.Message.HasContent()
+		if generated {
+			result = append(result, msg)
+		}
+		for _, child := range children[msg.Name] {
+			walk(child, generated)
+		}
+	}
+	walk(*start, true)
+
+	for _, msg := range e.Messages {
+		if !placed[msg.Name] {
+			return nil, fmt.Errorf("message %s of thread %s can't be imported, its parent %s is not part of the thread",
+				msg.Name, e.Thread.Name, msg.Spec.ParentMessageName)
+		}
+	}
+
+	return result, nil
+}
+
+// content points the images served by the API in content at the namespace the thread is imported to
+func (i *threadImporter) content(content []v1.ContentPart) (result []v1.ContentPart) {
+	replace := func(s string) string {
+		return imageURLPath.ReplaceAllStringFunc(s, func(path string) string {
+			match := imageURLPath.FindStringSubmatch(path)
+			if match[1] != i.from {
+				return path
+			}
+			return fmt.Sprintf("/namespaces/%s/images/%s/serve", i.namespace, match[2])
+		})
+	}
+
+	for _, part := range content {
+		part.Text = replace(part.Text)
+		if part.Image != nil {
+			image := *part.Image
+			image.URL = replace(image.URL)
+			part.Image = &image
+		}
+		result = append(result, part)
+	}
+	return result
+}
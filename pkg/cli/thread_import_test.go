@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testMessage(name, parentName, text string) v1.Message {
+	msg := v1.Message{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1.MessageSpec{ParentMessageName: parentName},
+	}
+	if text != "" {
+		msg.Status.Message.Content = v1.Text(text)
+	}
+	return msg
+}
+
+func TestMessagesByParent(t *testing.T) {
+	export := threadExport{
+		Thread: v1.Thread{
+			ObjectMeta: metav1.ObjectMeta{Name: "t"},
+			Spec:       v1.ThreadSpec{StartMessageName: "start"},
+		},
+		// Sorted by name, as messages created in the same second are exported
+		Messages: []v1.Message{
+			testMessage("a-resp", "start", "hi"),
+			testMessage("b-next", "a-resp", "again"),
+			testMessage("c-pending", "b-next", ""),
+			testMessage("d-below-pending", "c-pending", "never"),
+			testMessage("start", "", "hello"),
+		},
+	}
+
+	msgs, err := export.messagesByParent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, msg := range msgs {
+		names = append(names, msg.Name)
+	}
+	if want := []string{"start", "a-resp", "b-next"}; !slices.Equal(names, want) {
+		t.Errorf("messagesByParent() = %v, want %v", names, want)
+	}
+}
+
+func TestMessagesByParentMissingParent(t *testing.T) {
+	export := threadExport{
+		Thread: v1.Thread{
+			ObjectMeta: metav1.ObjectMeta{Name: "t"},
+			Spec:       v1.ThreadSpec{StartMessageName: "start"},
+		},
+		Messages: []v1.Message{
+			testMessage("start", "", "hello"),
+			testMessage("orphan", "missing", "hi"),
+		},
+	}
+
+	if _, err := export.messagesByParent(); err == nil {
+		t.Error("messagesByParent() returned no error for a message without its parent")
+	}
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var phases = []v1.Phase{
+	v1.PhasePending,
+	v1.PhaseGenerating,
+	v1.PhaseAwaitingTools,
+	v1.PhaseComplete,
+	v1.PhaseFailed,
+	v1.PhaseCancelled,
+}
+
+type ThreadList struct {
+	thread *Thread
+
+	Assistant string `usage:"Only list the threads of the assistant" short:"a" local:"true"`
+	Since     string `usage:"Only list the threads active within the duration, like 1h or 30m" local:"true"`
+	Phase     string `usage:"Only list the threads in the phase, like Complete or Failed" local:"true"`
+	All       bool   `usage:"Include the threads of assistants called by other threads" local:"true"`
+}
+
+func (t *ThreadList) Customize(cmd *cobra.Command) {
+	cmd.Use = "list [flags]"
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = "List threads, the most recently active first"
+	cmd.Args = cobra.NoArgs
+}
+
+func (t *ThreadList) Run(cmd *cobra.Command, args []string) error {
+	var (
+		since time.Duration
+		phase v1.Phase
+		err   error
+	)
+	if t.Since != "" {
+		if since, err = time.ParseDuration(t.Since); err != nil {
+			return fmt.Errorf("invalid duration %s: %w", t.Since, err)
+		}
+	}
+	if t.Phase != "" {
+		for _, p := range phases {
+			if strings.EqualFold(string(p), t.Phase) {
+				phase = p
+			}
+		}
+		if phase == "" {
+			return fmt.Errorf("invalid phase %s, must be one of %v", t.Phase, phases)
+		}
+	}
+
+	client, err := t.thread.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var threads v1.ThreadList
+	if err := client.List(cmd.Context(), &threads, &kclient.ListOptions{
+		Namespace: t.thread.Namespace,
+	}); err != nil {
+		return err
+	}
+
+	var result []v1.Thread
+	for _, thread := range threads.Items {
+		switch {
+		case !t.All && thread.Spec.ParentThreadName != "":
+		case t.Assistant != "" && thread.Spec.AssistantName != t.Assistant:
+		case phase != "" && thread.Status.Phase != phase:
+		case since > 0 && time.Since(lastActive(&thread)) > since:
+		default:
+			result = append(result, thread)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b v1.Thread) int {
+		return lastActive(&b).Compare(lastActive(&a))
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tASSISTANT\tPHASE\tMESSAGES\tTOOL CALLS\tLAST ACTIVE\tDESCRIPTION")
+	for _, thread := range result {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n", thread.Name, thread.Spec.AssistantName,
+			orNone(string(thread.Status.Phase)), thread.Status.MessageCount, thread.Status.ToolCallCount,
+			duration.HumanDuration(time.Since(lastActive(&thread)))+" ago", firstLine(thread.Status.Description))
+	}
+	return w.Flush()
+}
+
+// lastActive is the time of the last message of the thread, or when it was created if it has none
+func lastActive(thread *v1.Thread) time.Time {
+	if thread.Status.LastMessageTime != nil {
+		return thread.Status.LastMessageTime.Time
+	}
+	return thread.CreationTimestamp.Time
+}
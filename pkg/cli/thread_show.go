@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type ThreadShow struct {
+	thread *Thread
+}
+
+func (t *ThreadShow) Customize(cmd *cobra.Command) {
+	cmd.Use = "show THREAD"
+	cmd.Short = "Print a thread and the messages on its active branch, including tool calls and their results"
+	cmd.Args = cobra.ExactArgs(1)
+}
+
+func (t *ThreadShow) Run(cmd *cobra.Command, args []string) error {
+	client, err := t.thread.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var thread v1.Thread
+	if err := client.Get(cmd.Context(), router.Key(t.thread.Namespace, args[0]), &thread); err != nil {
+		return err
+	}
+
+	msgs, err := activeBranch(cmd.Context(), client, &thread)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Thread:\t%s\n", thread.Name)
+	_, _ = fmt.Fprintf(w, "Assistant:\t%s\n", thread.Spec.AssistantName)
+	if thread.Status.Description != "" {
+		_, _ = fmt.Fprintf(w, "Description:\t%s\n", thread.Status.Description)
+	}
+	if thread.Spec.ParentThreadName != "" {
+		_, _ = fmt.Fprintf(w, "Called From:\t%s\n", thread.Spec.ParentThreadName)
+	}
+	_, _ = fmt.Fprintf(w, "Phase:\t%s\n", orNone(string(thread.Status.Phase)))
+	_, _ = fmt.Fprintf(w, "Created:\t%s\n", thread.CreationTimestamp.UTC().Format("2006-01-02 15:04:05 MST"))
+	_, _ = fmt.Fprintf(w, "Messages:\t%d (%d tool calls)\n", thread.Status.MessageCount, thread.Status.ToolCallCount)
+	if usage := thread.Status.Usage; usage != nil {
+		_, _ = fmt.Fprintf(w, "Tokens:\t%d (%d prompt, %d completion)\n", usage.TotalTokens, usage.PromptTokens,
+			usage.CompletionTokens)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, msg := range msgs {
+		fmt.Println()
+		fmt.Println(formatMessage(&msg))
+	}
+	return nil
+}
+
+// activeBranch returns the messages on the active branch of the thread, from the first to the last
+func activeBranch(ctx context.Context, c kclient.Client, thread *v1.Thread) (result []v1.Message, _ error) {
+	next := thread.Spec.StartMessageName
+	for next != "" {
+		var msg v1.Message
+		if err := c.Get(ctx, router.Key(thread.Namespace, next), &msg); apierrors.IsNotFound(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, msg)
+		next = msg.Status.NextMessageName
+	}
+	return result, nil
+}
+
+// formatMessage returns the message as the chat prints it, with the time it was created, and why it did not
+// complete if it failed or was cancelled
+func formatMessage(msg *v1.Message) string {
+	content := fmt.Sprintf("[%s %s]: %s", msg.CreationTimestamp.Local().Format("15:04:05"), msg.Name,
+		msg.Status.Message.String())
+	switch msg.Status.Phase {
+	case v1.PhaseFailed:
+		reason := "failed"
+		if condition := meta.FindStatusCondition(msg.Status.Conditions, "Controller"); condition != nil &&
+			condition.Status == metav1.ConditionFalse && condition.Message != "" {
+			reason += ": " + condition.Message
+		}
+		content += fmt.Sprintf(" (%s)", reason)
+	case v1.PhaseCancelled:
+		content += " (cancelled)"
+	}
+	return content
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/baaah/pkg/watcher"
+	"github.com/spf13/cobra"
+)
+
+type ThreadTail struct {
+	thread *Thread
+
+	Lines  int  `usage:"Number of messages to print, 0 for all of them" default:"10" local:"true"`
+	Follow bool `usage:"Keep printing the messages of the thread as they are generated" short:"f" local:"true"`
+}
+
+func (t *ThreadTail) Customize(cmd *cobra.Command) {
+	cmd.Use = "tail [flags] THREAD"
+	cmd.Short = "Print the last messages on the active branch of a thread, and follow it with -f"
+	cmd.Args = cobra.ExactArgs(1)
+}
+
+func (t *ThreadTail) Run(cmd *cobra.Command, args []string) error {
+	client, err := t.thread.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	thread := &v1.Thread{}
+	if err := client.Get(cmd.Context(), router.Key(t.thread.Namespace, args[0]), thread); err != nil {
+		return err
+	}
+
+	msgs, err := activeBranch(cmd.Context(), client, thread)
+	if err != nil {
+		return err
+	}
+	if t.Lines > 0 && len(msgs) > t.Lines {
+		msgs = msgs[len(msgs)-t.Lines:]
+	}
+
+	if !t.Follow {
+		for _, msg := range msgs {
+			fmt.Println(formatMessage(&msg))
+		}
+		return nil
+	}
+
+	// The last message is printed while it is followed, since it may not be generated yet
+	var next string
+	if len(msgs) > 0 {
+		for _, msg := range msgs[:len(msgs)-1] {
+			fmt.Println(formatMessage(&msg))
+		}
+		next = msgs[len(msgs)-1].Name
+	} else {
+		thread, err = watcher.New[*v1.Thread](client).ByObject(cmd.Context(), thread, func(thread *v1.Thread) (bool, error) {
+			return thread.Spec.StartMessageName != "", nil
+		})
+		if err != nil {
+			return err
+		}
+		next = thread.Spec.StartMessageName
+	}
+
+	for {
+		var (
+			printed string
+			done    bool
+		)
+		msg, err := watcher.New[*v1.Message](client).ByName(cmd.Context(), thread.Namespace, next, func(msg *v1.Message) (bool, error) {
+			if msg.Status.Message.HasContent() || msg.Status.Phase.Done() {
+				content := formatMessage(msg)
+				if !strings.HasPrefix(content, printed) {
+					// The content was generated again, so start over
+					fmt.Println()
+					printed = ""
+				}
+				fmt.Print(strings.TrimPrefix(content, printed))
+				printed = content
+			}
+			if msg.Status.Phase.Done() && !done {
+				fmt.Println()
+				done = true
+			}
+			// Wait for the next message, the thread may go on after the last response of the assistant
+			return done && msg.Status.NextMessageName != "", nil
+		})
+		if err != nil {
+			return err
+		}
+		next = msg.Status.NextMessageName
+	}
+}
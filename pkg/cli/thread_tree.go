@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/spf13/cobra"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type ThreadTree struct {
+	thread *Thread
+
+	Assistant string `usage:"Only print the threads of the assistant, when no thread is given" short:"a" local:"true"`
+}
+
+func (t *ThreadTree) Customize(cmd *cobra.Command) {
+	cmd.Use = "tree [flags] [THREAD]"
+	cmd.Short = "Print a thread, or all threads, with the tools they called and the threads of the assistants they called"
+	cmd.Args = cobra.MaximumNArgs(1)
+}
+
+func (t *ThreadTree) Run(cmd *cobra.Command, args []string) error {
+	client, err := t.thread.API.Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var (
+		objects threadObjects
+		opts    = &kclient.ListOptions{Namespace: t.thread.Namespace}
+	)
+	if err := client.List(cmd.Context(), &objects.threads, opts); err != nil {
+		return err
+	}
+	if err := client.List(cmd.Context(), &objects.invokes, opts); err != nil {
+		return err
+	}
+	sortByCreation(objects.threads.Items)
+	sortByCreation(objects.invokes.Items)
+
+	var roots []v1.Thread
+	for _, thread := range objects.threads.Items {
+		switch {
+		case len(args) > 0 && thread.Name == args[0]:
+		case len(args) > 0:
+			continue
+		case thread.Spec.ParentThreadName != "":
+			continue
+		case t.Assistant != "" && thread.Spec.AssistantName != t.Assistant:
+			continue
+		}
+		roots = append(roots, thread)
+	}
+	if len(args) > 0 && len(roots) == 0 {
+		return fmt.Errorf("thread %s not found", args[0])
+	}
+
+	buf := &strings.Builder{}
+	for _, thread := range roots {
+		buf.WriteString(threadLine(&thread) + "\n")
+		objects.tree(buf, &thread, "")
+	}
+	fmt.Print(buf.String())
+	return nil
+}
+
+// tree writes the tool calls of the thread, with the threads of the assistants they called below them. Threads
+// of assistants that can't be matched to a call, like the ones of imported threads, follow the calls.
+func (o *threadObjects) tree(buf *strings.Builder, thread *v1.Thread, prefix string) {
+	var (
+		children = map[string]*v1.Thread{}
+		calls    []v1.InvokeTool
+		called   []*v1.Thread
+	)
+	for i, child := range o.threads.Items {
+		if child.Spec.ParentThreadName == thread.Name {
+			children[child.Spec.StartMessageName] = &o.threads.Items[i]
+		}
+	}
+	for _, invoke := range o.invokes.Items {
+		if invoke.Spec.ThreadName == thread.Name {
+			calls = append(calls, invoke)
+		}
+	}
+	for i, child := range o.threads.Items {
+		if child.Spec.ParentThreadName == thread.Name && !calledBy(calls, &child) {
+			called = append(called, &o.threads.Items[i])
+		}
+	}
+
+	for i, invoke := range calls {
+		branch, indent := "├── ", "│   "
+		if i == len(calls)-1 && len(called) == 0 {
+			branch, indent = "└── ", "    "
+		}
+
+		buf.WriteString(fmt.Sprintf("%s%s%s(%s) %s\n", prefix, branch, invoke.Spec.ToolCall.Function.Name,
+			invoke.Spec.ToolCall.Function.Arguments, orNone(string(invoke.Status.Phase))))
+
+		if child, ok := children[invoke.Status.AssistantMessageName]; ok && invoke.Status.AssistantMessageName != "" {
+			buf.WriteString(prefix + indent + "└── " + threadLine(child) + "\n")
+			o.tree(buf, child, prefix+indent+"    ")
+		}
+	}
+
+	for i, child := range called {
+		branch, indent := "├── ", "│   "
+		if i == len(called)-1 {
+			branch, indent = "└── ", "    "
+		}
+		buf.WriteString(prefix + branch + threadLine(child) + "\n")
+		o.tree(buf, child, prefix+indent)
+	}
+}
+
+// calledBy is true if one of the calls started the thread
+func calledBy(calls []v1.InvokeTool, thread *v1.Thread) bool {
+	for _, invoke := range calls {
+		if invoke.Status.AssistantMessageName != "" && invoke.Status.AssistantMessageName == thread.Spec.StartMessageName {
+			return true
+		}
+	}
+	return false
+}
+
+func threadLine(thread *v1.Thread) string {
+	line := fmt.Sprintf("%s [%s] %s, %d messages", thread.Name, thread.Spec.AssistantName,
+		orNone(string(thread.Status.Phase)), thread.Status.MessageCount)
+	if thread.Status.Description != "" {
+		line += ": " + firstLine(thread.Status.Description)
+	}
+	return line
+}
@@ -0,0 +1,38 @@
+package assistant
+
+import (
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"k8s.io/apimachinery/pkg/fields"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Usage totals the token usage of all the threads of the assistant
+func Usage(req router.Request, resp router.Response) error {
+	var (
+		assistant = req.Object.(*v1.Assistant)
+		threads   v1.ThreadList
+		totals    v1.UsageTotals
+	)
+
+	if err := req.List(&threads, &kclient.ListOptions{
+		Namespace:     assistant.Namespace,
+		FieldSelector: fields.OneTermEqualSelector(v1.ThreadAssistantNameField, assistant.Name),
+	}); err != nil {
+		return err
+	}
+
+	for _, thread := range threads.Items {
+		if thread.Spec.AssistantName != assistant.Name || thread.Status.Usage == nil {
+			continue
+		}
+		totals.AddTotals(*thread.Status.Usage)
+	}
+
+	if totals == (v1.UsageTotals{}) {
+		assistant.Status.Usage = nil
+	} else {
+		assistant.Status.Usage = &totals
+	}
+	return nil
+}
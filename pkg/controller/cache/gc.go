@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sweepInterval is the minimum time between two checks of the total size of the caches in a namespace
+const sweepInterval = 30 * time.Second
+
+// GC deletes caches that have not been used for longer than TTL, and the least recently used caches of a
+// namespace once their total size exceeds MaxSize. A zero TTL or MaxSize disables that policy.
+type GC struct {
+	TTL     time.Duration
+	MaxSize int64
+	// Client is used to list all the caches of a namespace without registering a trigger on every one of them
+	Client kclient.Client
+
+	lock      sync.Mutex
+	lastSweep map[string]time.Time
+}
+
+func (g *GC) Handle(req router.Request, resp router.Response) error {
+	cache := req.Object.(*v1.Cache)
+	cache.Status.Size = int64(len(cache.Content))
+
+	if g.TTL > 0 {
+		remaining := time.Until(cache.LastUsed().Add(g.TTL))
+		if remaining <= 0 {
+			return ignoreNotFound(req.Client.Delete(req.Ctx, cache))
+		}
+		resp.RetryAfter(remaining)
+	}
+
+	if g.MaxSize > 0 {
+		if !g.shouldSweep(cache.Namespace) {
+			// Check again once the namespace can be swept, it may have gone over MaxSize since the last sweep
+			resp.RetryAfter(sweepInterval)
+			return nil
+		}
+		return g.sweep(req.Ctx, cache.Namespace)
+	}
+
+	return nil
+}
+
+func (g *GC) shouldSweep(namespace string) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if time.Since(g.lastSweep[namespace]) < sweepInterval {
+		return false
+	}
+	if g.lastSweep == nil {
+		g.lastSweep = map[string]time.Time{}
+	}
+	g.lastSweep[namespace] = time.Now()
+	return true
+}
+
+// sweep deletes the least recently used caches of namespace until their total size is at most MaxSize
+func (g *GC) sweep(ctx context.Context, namespace string) error {
+	var caches v1.CacheList
+	if err := g.Client.List(ctx, &caches, &kclient.ListOptions{
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	var total int64
+	for _, cache := range caches.Items {
+		total += int64(len(cache.Content))
+	}
+	if total <= g.MaxSize {
+		return nil
+	}
+
+	sort.Slice(caches.Items, func(i, j int) bool {
+		left, right := caches.Items[i].LastUsed(), caches.Items[j].LastUsed()
+		return left.Before(&right)
+	})
+
+	for i := range caches.Items {
+		if total <= g.MaxSize {
+			break
+		}
+		if err := ignoreNotFound(g.Client.Delete(ctx, &caches.Items[i])); err != nil {
+			return err
+		}
+		total -= int64(len(caches.Items[i].Content))
+	}
+
+	return nil
+}
+
+func ignoreNotFound(err error) error {
+	if apierror.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -10,10 +10,15 @@ import (
 )
 
 type Options struct {
-	ApiUrl    string `json:"apiUrl,omitempty" default:"http://localhost:8080"`
-	ApiToken  string `json:"apiToken,omitempty"`
-	Namespace string `usage:"Namespace to watch" default:"acorn"`
-	AppName   string `usage:"App to create assistants for"`
+	ApiUrl       string `json:"apiUrl,omitempty" default:"http://localhost:8080"`
+	ApiToken     string `json:"apiToken,omitempty" usage:"Admin token of the API server, used to publish the content of messages as it is generated"`
+	ApiTokenFile string `usage:"File to read the API token from if it is not passed, such as the admin token file of the server" default:"admin-token"`
+	Namespace    string `usage:"Namespace to watch" default:"acorn"`
+	AppName      string `usage:"App to create assistants for"`
+	Provider     string `usage:"Default model provider for assistants that don't set one (openai, anthropic, ollama)"`
+	PriceTable   string `usage:"YAML or JSON file of model name prefixes to the prompt and completion price of one million tokens"`
+	CacheTTL     string `usage:"Delete cached responses that have not been used for this long, such as 168h (default never)"`
+	CacheSize    string `usage:"Delete the least recently used cached responses once a namespace exceeds this size, such as 500Mi (default unlimited)"`
 }
 
 type Controller struct {
@@ -32,6 +37,10 @@ func New(ctx context.Context, opt Options) (*Controller, error) {
 		return nil, err
 	}
 
+	if err := index(ctx, services.Router.Backend()); err != nil {
+		return nil, err
+	}
+
 	return &Controller{
 		router:   services.Router,
 		services: services,
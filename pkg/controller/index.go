@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"context"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/backend"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// index adds the field indexes that handlers list by, so that totals of a thread or an assistant only list
+// and are only triggered by their own messages and threads
+func index(ctx context.Context, b backend.Backend) error {
+	if err := b.IndexField(ctx, &v1.Message{}, v1.MessageThreadNameField, func(obj kclient.Object) []string {
+		return []string{obj.(*v1.Message).Status.ThreadName}
+	}); err != nil {
+		return err
+	}
+	return b.IndexField(ctx, &v1.Thread{}, v1.ThreadAssistantNameField, func(obj kclient.Object) []string {
+		return []string{obj.(*v1.Thread).Spec.AssistantName}
+	})
+}
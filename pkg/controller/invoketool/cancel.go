@@ -0,0 +1,62 @@
+package invoketool
+
+import (
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isCancelled returns true if invoke was cancelled before it returned
+func isCancelled(invoke *v1.InvokeTool) bool {
+	return invoke.Spec.Cancel && (len(invoke.Status.Content) == 0 || invoke.Status.InProgress ||
+		meta.IsStatusConditionTrue(invoke.Status.Conditions, v1.InvokeToolConditionCancelled))
+}
+
+// cancel stops invoke, and the thread of the assistant it called if any
+func cancel(req router.Request, resp router.Response, invoke *v1.InvokeTool) error {
+	// Keep the thread of the called assistant
+	resp.DisablePrune()
+
+	if invoke.Status.AssistantMessageName != "" {
+		if err := cancelMessages(req, invoke.Status.AssistantMessageName); err != nil {
+			return err
+		}
+	}
+
+	invoke.Status.InProgress = false
+	invoke.Status.Generation = invoke.Generation
+	meta.SetStatusCondition(&invoke.Status.Conditions, metav1.Condition{
+		Type:               v1.InvokeToolConditionCancelled,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: invoke.Generation,
+		Reason:             "Cancelled",
+		Message:            "the message calling the tool was cancelled",
+	})
+	return nil
+}
+
+// cancelMessages sets spec.cancel on the messages starting at next, which in turn cancels the tools they call
+func cancelMessages(req router.Request, next string) error {
+	for next != "" {
+		var msg v1.Message
+		if err := req.Get(&msg, req.Namespace, next); apierror.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if !msg.Spec.Cancel {
+			patch := kclient.MergeFrom(msg.DeepCopy())
+			msg.Spec.Cancel = true
+			if err := req.Client.Patch(req.Ctx, &msg, patch); err != nil {
+				return err
+			}
+		}
+
+		next = msg.Status.NextMessageName
+	}
+	return nil
+}
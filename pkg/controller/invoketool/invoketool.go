@@ -9,8 +9,13 @@ import (
 func Handle(req router.Request, resp router.Response) error {
 	invoke := req.Object.(*v1.InvokeTool)
 
+	if isCancelled(invoke) {
+		return cancel(req, resp, invoke)
+	}
+
 	var (
 		assistant v1.Assistant
+		caller    v1.Assistant
 		thread    v1.Thread
 	)
 
@@ -18,12 +23,27 @@ func Handle(req router.Request, resp router.Response) error {
 		return err
 	}
 
-	if err := req.Get(&assistant, req.Namespace, invoke.Spec.ToolCall.Function.Name); apierror.IsNotFound(err) {
+	if err := req.Get(&caller, req.Namespace, thread.Spec.AssistantName); err != nil {
+		return err
+	}
+
+	if valid, err := validateArguments(invoke, &caller); err != nil {
+		return err
+	} else if !valid {
+		invoke.Status.InProgress = false
+		invoke.Status.Generation = invoke.Generation
+		return nil
+	}
+
+	if function := caller.Function(invoke.Spec.ToolCall.Function.Name); function != nil && function.Client {
+		// The client calls the function and sets the output
+		if invoke.Spec.Output != nil {
+			invoke.Status.Content = v1.Text(*invoke.Spec.Output)
+			invoke.Status.InProgress = false
+		}
+	} else if err := req.Get(&assistant, req.Namespace, invoke.Spec.ToolCall.Function.Name); apierror.IsNotFound(err) {
 		if len(invoke.Status.Content) == 0 || invoke.Generation != invoke.Status.Generation {
-			if err := req.Get(&assistant, req.Namespace, thread.Spec.AssistantName); err != nil {
-				return err
-			}
-			body, err := callFunc(req.Ctx, &assistant, invoke.Spec.ToolCall)
+			body, err := callFunc(req.Ctx, &caller, invoke.Spec.ToolCall)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,27 @@
+package invoketool
+
+import (
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Phase sets the phase of the tool call from its status
+func Phase(req router.Request, resp router.Response) error {
+	invoke := req.Object.(*v1.InvokeTool)
+
+	switch {
+	case meta.IsStatusConditionTrue(invoke.Status.Conditions, v1.InvokeToolConditionCancelled):
+		invoke.Status.Phase = v1.PhaseCancelled
+	case meta.IsStatusConditionFalse(invoke.Status.Conditions, "Controller"):
+		invoke.Status.Phase = v1.PhaseFailed
+	case len(invoke.Status.Content) == 0:
+		invoke.Status.Phase = v1.PhasePending
+	case invoke.Status.InProgress:
+		invoke.Status.Phase = v1.PhaseGenerating
+	default:
+		invoke.Status.Phase = v1.PhaseComplete
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package invoketool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/acorn-io/aml/pkg/jsonschema"
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/schema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// validateArguments checks the arguments of call against the parameters of the matching tool of assistant.
+// If they don't match, the content of invoke is set to a description of the errors, for the model to correct
+// the call, and false is returned.
+func validateArguments(invoke *v1.InvokeTool, assistant *v1.Assistant) (bool, error) {
+	var (
+		call   = invoke.Spec.ToolCall
+		params *jsonschema.Schema
+		args   = call.Function.Arguments
+	)
+
+	if function := assistant.Function(call.Function.Name); function != nil {
+		params = function.Parameters
+	}
+
+	if strings.TrimSpace(args) == "" {
+		args = "{}"
+	}
+
+	var validationErr *schema.ValidationError
+	if err := schema.Validate(params, args); errors.As(err, &validationErr) {
+		invoke.Status.Content = v1.Text(fmt.Sprintf("The arguments to %s are invalid, nothing was called:\n%s\n\n"+
+			"Correct the arguments and call %s again.", call.Function.Name, strings.Join(validationErr.Errors, "\n"),
+			call.Function.Name))
+		meta.SetStatusCondition(&invoke.Status.Conditions, metav1.Condition{
+			Type:               v1.InvokeToolConditionArgumentsValid,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: invoke.Generation,
+			Reason:             "SchemaValidationFailed",
+			Message:            validationErr.Error(),
+		})
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	meta.RemoveStatusCondition(&invoke.Status.Conditions, v1.InvokeToolConditionArgumentsValid)
+	return true, nil
+}
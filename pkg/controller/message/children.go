@@ -0,0 +1,41 @@
+package message
+
+import (
+	"slices"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Children drops deleted messages from the children of the message. If the child on the active branch was
+// deleted, the newest remaining child becomes active.
+func Children(req router.Request, resp router.Response) error {
+	var (
+		msg      = req.Object.(*v1.Message)
+		children []string
+	)
+
+	for _, childName := range msg.Status.ChildMessageNames {
+		var child v1.Message
+		if err := req.Get(&child, msg.Namespace, childName); apierror.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		children = append(children, childName)
+	}
+
+	if len(children) == len(msg.Status.ChildMessageNames) {
+		return nil
+	}
+
+	msg.Status.ChildMessageNames = children
+	if msg.Status.NextMessageName != "" && !slices.Contains(children, msg.Status.NextMessageName) {
+		msg.Status.NextMessageName = ""
+		if len(children) > 0 {
+			msg.Status.NextMessageName = children[len(children)-1]
+		}
+	}
+	return nil
+}
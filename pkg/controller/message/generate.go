@@ -2,31 +2,49 @@ package message
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
 	openai2 "github.com/acorn-io/assistant-runtime/pkg/openai"
+	"github.com/acorn-io/assistant-runtime/pkg/prices"
 	"github.com/acorn-io/baaah/pkg/conditions"
-	"github.com/acorn-io/baaah/pkg/name"
 	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/z"
 	"github.com/sashabaranov/go-openai"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+var errCancelled = errors.New("message was cancelled")
+
+const (
+	maxAttempts    = 8
+	initialBackoff = 2 * time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
 type CompleteClient interface {
-	Call(ctx context.Context, k8s kclient.Client, namespace string, messageRequest openai2.CompletionRequest, status chan<- v1.MessageBody) (*v1.MessageBody, error)
+	Call(ctx context.Context, k8s kclient.Client, namespace string, messageRequest openai2.CompletionRequest, status chan<- v1.MessageBody) (*v1.MessageBody, v1.Usage, error)
+	// Model returns the model the request is sent to, which is a default model if the request doesn't set one
+	Model(ctx context.Context, k8s kclient.Client, namespace string, messageRequest openai2.CompletionRequest) (string, error)
 }
 
-func NewGenerateHandler(c CompleteClient) *Handler {
+func NewGenerateHandler(c CompleteClient, prices prices.Table, publisher *Publisher) *Handler {
 	return &Handler{
 		oaiClient: c,
+		prices:    prices,
+		publisher: publisher,
 	}
 }
 
 type Handler struct {
 	oaiClient CompleteClient
+	prices    prices.Table
+	publisher *Publisher
 }
 
 func (h *Handler) CompleteAssistant(req router.Request, resp router.Response) error {
@@ -41,6 +59,29 @@ func (h *Handler) CompleteAssistant(req router.Request, resp router.Response) er
 		return nil
 	}
 
+	if msg.Spec.Cancel {
+		if !msg.Status.Message.HasContent() || msg.Status.InProgress {
+			markCancelled(msg, "cancelled before generation completed")
+		}
+		return nil
+	}
+
+	if msg.Status.Message.HasContent() && !msg.Status.InProgress {
+		// Already completed
+		return nil
+	}
+
+	if msg.Status.Attempts >= maxAttempts {
+		return conditions.NewErrTerminalf("giving up after %d attempts", msg.Status.Attempts)
+	}
+
+	if msg.Status.RunAfter != nil {
+		if wait := time.Until(msg.Status.RunAfter.Time); wait > 0 {
+			resp.RetryAfter(wait)
+			return nil
+		}
+	}
+
 	msg.Status.InProgress = true
 
 	if err := req.Get(&thread, msg.Namespace, msg.Status.ThreadName); err != nil {
@@ -52,12 +93,19 @@ func (h *Handler) CompleteAssistant(req router.Request, resp router.Response) er
 	}
 
 	request := openai2.CompletionRequest{
-		Model:        assistant.Spec.Model,
-		Tools:        assistant.Spec.Tools,
-		Vision:       assistant.Spec.Vision,
-		MaxToken:     assistant.Spec.MaxTokens,
-		JSONResponse: assistant.Spec.JSONResponse,
-		Cache:        assistant.Spec.Cache,
+		Provider:       assistant.Spec.Provider,
+		Model:          assistant.Spec.Model,
+		Tools:          assistant.Spec.Tools,
+		Vision:         assistant.Spec.Vision,
+		MaxToken:       assistant.Spec.MaxTokens,
+		JSONResponse:   assistant.Spec.JSONResponse,
+		ResponseSchema: assistant.Spec.ResponseSchema,
+		Cache:          assistant.Spec.Cache,
+		Seed:           msg.Spec.Input.Seed,
+		CacheLabels: map[string]string{
+			v1.CacheAssistantLabel: assistant.Name,
+			v1.CacheThreadLabel:    thread.Name,
+		},
 	}
 
 	var msgs []v1.Message
@@ -68,8 +116,9 @@ func (h *Handler) CompleteAssistant(req router.Request, resp router.Response) er
 		msgs = append(msgs, *parent.DeepCopy())
 	}
 
+	var system, history []v1.MessageBody
 	if assistant.Spec.Instructions != "" {
-		request.Messages = append(request.Messages, v1.MessageBody{
+		system = append(system, v1.MessageBody{
 			Role:    openai.ChatMessageRoleSystem,
 			Content: v1.Text(assistant.Spec.Instructions),
 		})
@@ -80,17 +129,108 @@ func (h *Handler) CompleteAssistant(req router.Request, resp router.Response) er
 			// Not ready
 			return nil
 		}
-		request.Messages = append(request.Messages, msgs[i].Status.Message)
+		history = append(history, msgs[i].Status.Message)
 	}
 
-	if err := h.complete(req.Ctx, req.Client, msg, request); err != nil {
-		return err
+	msg.Status.Usage = nil
+	messages, err := h.truncate(req.Ctx, req.Client, msg, request, z.Dereference(assistant.Spec.Truncation), system, history)
+	if err == nil {
+		request.Messages = messages
+		err = h.complete(req.Ctx, req.Client, msg, request)
+	}
+	if err == nil && request.ResponseSchema != nil && !msg.Status.Message.IsToolCall() {
+		err = h.validateResponse(req.Ctx, req.Client, msg, request)
+	}
+	if errors.Is(err, errCancelled) {
+		markCancelled(msg, "cancelled during generation")
+		return nil
+	} else if openai2.IsTransient(err) {
+		return h.scheduleRetry(msg, resp, err)
+	} else if err != nil {
+		return conditions.NewErrTerminal(err)
+	}
+
+	if cond := meta.FindStatusCondition(msg.Status.Conditions, v1.MessageConditionRetrying); cond != nil {
+		meta.SetStatusCondition(&msg.Status.Conditions, metav1.Condition{
+			Type:               v1.MessageConditionRetrying,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: msg.Generation,
+			Reason:             "Succeeded",
+			Message:            fmt.Sprintf("completed after %d failed attempts", msg.Status.Attempts),
+		})
 	}
 
+	msg.Status.RunAfter = nil
 	msg.Status.InProgress = false
 	return nil
 }
 
+// scheduleRetry records a failed attempt and sets status.runAfter so the completion is tried again
+// after an exponential backoff, or the delay the provider asked for if that is longer.
+func (h *Handler) scheduleRetry(msg *v1.Message, resp router.Response, err error) error {
+	msg.Status.Attempts++
+	if msg.Status.Attempts >= maxAttempts {
+		meta.SetStatusCondition(&msg.Status.Conditions, metav1.Condition{
+			Type:               v1.MessageConditionRetrying,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: msg.Generation,
+			Reason:             "AttemptsExhausted",
+			Message:            fmt.Sprintf("attempt %d of %d failed: %v", msg.Status.Attempts, maxAttempts, err),
+		})
+		msg.Status.RunAfter = nil
+		return conditions.NewErrTerminalf("giving up after %d attempts: %w", msg.Status.Attempts, err)
+	}
+
+	delay := backoff(msg.Status.Attempts)
+	if retryAfter := openai2.RetryAfter(err); retryAfter > delay {
+		delay = retryAfter
+	}
+
+	// Drop any partial content from the failed attempt
+	msg.Status.Message = v1.MessageBody{
+		Role: msg.Status.Message.Role,
+	}
+	msg.Status.RunAfter = &metav1.Time{Time: time.Now().Add(delay)}
+	meta.SetStatusCondition(&msg.Status.Conditions, metav1.Condition{
+		Type:               v1.MessageConditionRetrying,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: msg.Generation,
+		Reason:             "TransientError",
+		Message: fmt.Sprintf("attempt %d of %d failed, retrying after %s: %v", msg.Status.Attempts, maxAttempts,
+			msg.Status.RunAfter.Format(time.RFC3339), err),
+	})
+
+	resp.RetryAfter(delay)
+	return nil
+}
+
+// markCancelled stops a message that is being generated, keeping the content generated so far
+func markCancelled(msg *v1.Message, message string) {
+	if msg.Status.Message.Role == "" {
+		msg.Status.Message.Role = v1.RoleTypeAssistant
+	}
+	msg.Status.InProgress = false
+	msg.Status.RunAfter = nil
+	meta.SetStatusCondition(&msg.Status.Conditions, metav1.Condition{
+		Type:               v1.MessageConditionCancelled,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: msg.Generation,
+		Reason:             "Cancelled",
+		Message:            message,
+	})
+}
+
+func backoff(attempts int) time.Duration {
+	delay := initialBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
+
 func (h *Handler) CreateAssistantMessage(req router.Request, resp router.Response) error {
 	var (
 		msg = req.Object.(*v1.Message)
@@ -104,29 +244,32 @@ func (h *Handler) CreateAssistantMessage(req router.Request, resp router.Respons
 		return nil
 	}
 
-	response := v1.Message{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name.SafeHashConcatName(msg.Name, "resp"),
-			Namespace: req.Namespace,
-		},
-		Spec: v1.MessageSpec{
-			Input: v1.MessageInput{
-				Completion: true,
+	for i := 0; i <= msg.Spec.Alternatives; i++ {
+		resp.Objects(&v1.Message{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      msg.ResponseName(i),
+				Namespace: req.Namespace,
 			},
-			ParentMessageName: msg.Name,
-		},
+			Spec: v1.MessageSpec{
+				Input: v1.MessageInput{
+					Completion: true,
+					Seed:       i,
+				},
+				ParentMessageName: msg.Name,
+			},
+		})
 	}
 
-	msg.Status.NextMessageName = response.Name
-	resp.Objects(&response)
+	if msg.Status.NextMessageName == "" {
+		msg.Status.NextMessageName = msg.ResponseName(0)
+	}
 	return nil
 }
 
-func (h *Handler) runProgress(ctx context.Context, c kclient.Client, msg *v1.Message, progress chan v1.MessageBody) {
-	var (
-		current v1.MessageBody
-	)
-
+// runProgress publishes the content received on progress as it arrives, and saves it to the status of msg every
+// second until ctx is done. It also checks whether msg was cancelled, and if so calls cancel to abort the
+// completion. It returns the last content received, and whether msg was cancelled.
+func (h *Handler) runProgress(ctx context.Context, c kclient.Client, msg *v1.Message, progress chan v1.MessageBody, pub *publication, cancel func()) (current v1.MessageBody, cancelled bool) {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
@@ -136,11 +279,19 @@ loop:
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			if !cancelled && isCancelled(ctx, c, msg) {
+				// Keep receiving until the completion returns, so it is never blocked sending progress
+				cancelled = true
+				cancel()
+			}
 			if current.HasContent() {
 				msg.Status.Message = current
 				_ = c.Status().Update(ctx, msg)
 			}
 		case msg, ok := <-progress:
+			if len(msg.Content) > 0 {
+				pub.Send(msg.Content[0].Text)
+			}
 			if len(msg.Content) > 0 && len(current.Content) > 0 && msg.Content[0].Text != "" &&
 				msg.Content[0].Text != current.Content[0].Text {
 				content := msg.Content[0]
@@ -154,37 +305,72 @@ loop:
 		}
 	}
 
+	return
+}
+
+// isCancelled checks the latest version of msg for spec.cancel, which is set while msg is being generated
+func isCancelled(ctx context.Context, c kclient.Client, msg *v1.Message) bool {
+	var latest v1.Message
+	if err := c.Get(ctx, router.Key(msg.Namespace, msg.Name), &latest); err != nil {
+		return false
+	}
+	return latest.Spec.Cancel
 }
 
-func (h *Handler) progress(ctx context.Context, k8s kclient.Client, msg *v1.Message) (chan<- v1.MessageBody, func()) {
+// progress starts saving the progress of msg. The returned function stops it, and returns the last content
+// received and whether msg was cancelled.
+func (h *Handler) progress(ctx context.Context, k8s kclient.Client, msg *v1.Message, pub *publication, cancelCall func()) (chan<- v1.MessageBody, func() (v1.MessageBody, bool)) {
 	ctx, cancel := context.WithCancel(ctx)
 	progress := make(chan v1.MessageBody, 2)
 
-	wg := sync.WaitGroup{}
+	var (
+		current   v1.MessageBody
+		cancelled bool
+		wg        = sync.WaitGroup{}
+	)
 	wg.Add(1)
 	go func() {
-		h.runProgress(ctx, k8s, msg, progress)
+		current, cancelled = h.runProgress(ctx, k8s, msg, progress, pub, cancelCall)
 		close(progress)
 		wg.Done()
 	}()
 
-	return progress, func() {
+	return progress, func() (v1.MessageBody, bool) {
 		cancel()
 		wg.Wait()
+		return current, cancelled
 	}
 }
 
 func (h *Handler) complete(ctx context.Context, c kclient.Client, message *v1.Message, request openai2.CompletionRequest) error {
-	progress, cancel := h.progress(ctx, c, message)
-	defer cancel()
+	callCtx, cancelCall := context.WithCancel(ctx)
+	defer cancelCall()
 
-	result, err := h.oaiClient.Call(ctx, c, message.Namespace, request, progress)
-	if err != nil {
-		return conditions.NewErrTerminal(err)
-	}
+	pub := h.publisher.Open(callCtx, message)
+	defer pub.Close()
+
+	progress, stop := h.progress(ctx, c, message, pub, cancelCall)
+	defer stop()
 
-	cancel()
+	result, usage, err := h.oaiClient.Call(callCtx, c, message.Namespace, request, progress)
+	if partial, cancelled := stop(); cancelled && err != nil {
+		if partial.HasContent() {
+			message.Status.Message = partial
+		}
+		return errCancelled
+	} else if err != nil {
+		return err
+	}
 
 	message.Status.Message = *result
+	h.addUsage(message, usage)
 	return nil
 }
+
+func (h *Handler) addUsage(message *v1.Message, usage v1.Usage) {
+	usage.Cost = h.prices.Cost(usage)
+	if message.Status.Usage != nil {
+		usage = message.Status.Usage.Add(usage)
+	}
+	message.Status.Usage = &usage
+}
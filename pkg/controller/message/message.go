@@ -2,6 +2,7 @@ package message
 
 import (
 	"context"
+	"slices"
 
 	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
 	"github.com/acorn-io/baaah/pkg/conditions"
@@ -34,28 +35,45 @@ func setThreadName(ctx context.Context, c kclient.Client, msg *v1.Message) error
 	if err := c.Get(ctx, router.Key(msg.Namespace, msg.Spec.ParentMessageName), &parent); err != nil {
 		return err
 	}
-	if parent.Status.NextMessageName != msg.Name {
-		if parent.Status.NextMessageName != "" {
-			var siblingMsg v1.Message
-			if err := c.Get(ctx, router.Key(msg.Namespace, parent.Status.NextMessageName), &siblingMsg); apierror.IsNotFound(err) {
-			} else if err != nil {
-				return err
-			} else {
-				if err := c.Delete(ctx, &siblingMsg); err != nil {
-					return err
-				}
-			}
-		}
+	if !slices.Contains(parent.Status.ChildMessageNames, msg.Name) {
+		// A new child starts a new branch, which becomes the active one. Any other children are kept as
+		// alternatives.
+		parent.Status.ChildMessageNames = append(parent.Status.ChildMessageNames, msg.Name)
 		parent.Status.NextMessageName = msg.Name
 		if err := c.Status().Update(ctx, &parent); err != nil {
 			return err
 		}
+		if err := clearActiveMessage(ctx, c, msg, parent.Status.ThreadName); err != nil {
+			return err
+		}
 	}
 
 	msg.Status.ThreadName = parent.Status.ThreadName
 	return nil
 }
 
+// clearActiveMessage stops keeping the thread on the branch of its active message when msg starts a new branch,
+// so that the thread doesn't switch back from it
+func clearActiveMessage(ctx context.Context, c kclient.Client, msg *v1.Message, threadName string) error {
+	if threadName == "" {
+		return nil
+	}
+
+	var thread v1.Thread
+	if err := c.Get(ctx, router.Key(msg.Namespace, threadName), &thread); apierror.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if thread.Spec.ActiveMessageName == "" || thread.Spec.ActiveMessageName == msg.Name {
+		return nil
+	}
+
+	patch := kclient.MergeFrom(thread.DeepCopy())
+	thread.Spec.ActiveMessageName = ""
+	return c.Patch(ctx, &thread, patch)
+}
+
 func Initialize(req router.Request, resp router.Response) error {
 	msg := req.Object.(*v1.Message)
 
@@ -87,9 +105,12 @@ func Initialize(req router.Request, resp router.Response) error {
 
 	msg.Status.Message.Content = msg.Spec.Input.Content
 	msg.Status.Message.ToolCall = msg.Spec.Input.ToolCall
-	if msg.Status.Message.ToolCall == nil {
+	switch {
+	case msg.Spec.Input.Role != "":
+		msg.Status.Message.Role = msg.Spec.Input.Role
+	case msg.Status.Message.ToolCall == nil:
 		msg.Status.Message.Role = v1.RoleTypeUser
-	} else {
+	default:
 		msg.Status.Message.Role = v1.RoleTypeTool
 	}
 	return nil
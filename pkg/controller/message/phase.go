@@ -0,0 +1,75 @@
+package message
+
+import (
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Phase sets the phase of the message from its status and the phases of the tool calls it made
+func Phase(req router.Request, resp router.Response) error {
+	msg := req.Object.(*v1.Message)
+
+	phase, err := phase(req, msg)
+	if err != nil {
+		return err
+	}
+
+	msg.Status.Phase = phase
+	return nil
+}
+
+func phase(req router.Request, msg *v1.Message) (v1.Phase, error) {
+	switch {
+	case meta.IsStatusConditionTrue(msg.Status.Conditions, v1.MessageConditionCancelled):
+		return v1.PhaseCancelled, nil
+	case meta.IsStatusConditionFalse(msg.Status.Conditions, "Controller"):
+		return v1.PhaseFailed, nil
+	case msg.Status.RunAfter != nil:
+		// Waiting to retry
+		return v1.PhasePending, nil
+	case msg.Status.InProgress:
+		return v1.PhaseGenerating, nil
+	case !msg.Status.Message.HasContent():
+		return v1.PhasePending, nil
+	case !msg.Status.Message.IsToolCall():
+		return v1.PhaseComplete, nil
+	case msg.Spec.More && msg.Status.NextMessageName != "":
+		// The results of the tool calls were added as messages
+		return v1.PhaseComplete, nil
+	case len(msg.Status.InvokeToolNames) == 0:
+		return v1.PhaseAwaitingTools, nil
+	}
+
+	var awaiting, cancelled bool
+	for _, name := range msg.Status.InvokeToolNames {
+		var invoke v1.InvokeTool
+		if err := req.Get(&invoke, msg.Namespace, name); apierror.IsNotFound(err) {
+			return v1.PhaseAwaitingTools, nil
+		} else if err != nil {
+			return "", err
+		}
+
+		switch invoke.Status.Phase {
+		case v1.PhaseFailed:
+			return v1.PhaseFailed, nil
+		case v1.PhaseCancelled:
+			cancelled = true
+		case v1.PhaseComplete:
+		default:
+			awaiting = true
+		}
+	}
+
+	switch {
+	case awaiting:
+		return v1.PhaseAwaitingTools, nil
+	case cancelled:
+		return v1.PhaseCancelled, nil
+	case msg.Status.NextMessageName == "":
+		// The tool messages are not created yet
+		return v1.PhaseAwaitingTools, nil
+	}
+	return v1.PhaseComplete, nil
+}
@@ -0,0 +1,108 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"k8s.io/client-go/rest"
+)
+
+const publishBuffer = 1024
+
+// Publisher sends the content of messages as it is generated to the stream subresource of the messages, so that
+// clients get it before it is saved to the status
+type Publisher struct {
+	client *http.Client
+	url    string
+}
+
+func NewPublisher(cfg *rest.Config) (*Publisher, error) {
+	client, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Publisher{
+		client: client,
+		url:    strings.TrimSuffix(cfg.Host, "/"),
+	}, nil
+}
+
+// publication is the content of one generation of a message. Publishing is best effort, if the API server can't
+// keep up the rest of the content is dropped and clients get it from the status of the message.
+type publication struct {
+	deltas  chan v1.MessageDelta
+	dropped bool
+}
+
+// Open starts publishing the content of msg until the publication is closed. A nil Publisher returns a nil
+// publication, which drops everything.
+func (p *Publisher) Open(ctx context.Context, msg *v1.Message) *publication {
+	if p == nil {
+		return nil
+	}
+
+	var (
+		reader, writer = io.Pipe()
+		pub            = &publication{
+			deltas: make(chan v1.MessageDelta, publishBuffer),
+		}
+		url = fmt.Sprintf("%s/apis/%s/namespaces/%s/messages/%s/stream", p.url, v1.SchemeGroupVersion, msg.Namespace, msg.Name)
+	)
+
+	go func() {
+		// The request ends with its body when the publication is closed. The server only responds then, so the
+		// request isn't cancelled with ctx, which would hide the response.
+		req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, url, reader)
+		if err != nil {
+			_ = reader.CloseWithError(err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		resp, err := p.client.Do(req)
+		if err != nil {
+			slog.Warn("failed to publish message content", "message", msg.Name, "error", err)
+			_ = reader.CloseWithError(err)
+			return
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			slog.Warn("failed to publish message content", "message", msg.Name, "status", resp.Status)
+		}
+	}()
+
+	go func() {
+		enc := json.NewEncoder(writer)
+		for delta := range pub.deltas {
+			// Keep receiving after a failure so that Send never blocks
+			_ = enc.Encode(delta)
+		}
+		_ = writer.Close()
+	}()
+
+	return pub
+}
+
+// Send publishes text added to the message
+func (p *publication) Send(text string) {
+	if p == nil || p.dropped || text == "" {
+		return
+	}
+	select {
+	case p.deltas <- v1.MessageDelta{Text: text}:
+	default:
+		p.dropped = true
+	}
+}
+
+// Close ends the publication after the content sent so far
+func (p *publication) Close() {
+	if p != nil {
+		close(p.deltas)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/acorn-io/baaah/pkg/name"
 	"github.com/acorn-io/baaah/pkg/router"
 	apierror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -20,7 +21,8 @@ func InvokeTools(req router.Request, resp router.Response) error {
 		msg = req.Object.(*v1.Message)
 	)
 
-	if !IsToolCall(msg) {
+	// The results of the calls of a message with more messages follow as messages, the tools are not invoked
+	if !IsToolCall(msg) || msg.Spec.More {
 		return nil
 	}
 
@@ -52,25 +54,35 @@ func InvokeTools(req router.Request, resp router.Response) error {
 				ParentMessageName:   msg.Name,
 				ResponseMessageName: name.SafeConcatName(msg.Name, toolName),
 				ToolCall:            *call,
+				Cancel:              msg.Spec.Cancel,
 			},
 		})
 	}
 
 	var (
 		lastMessage = msg.Name
+		invokes     = make([]v1.InvokeTool, len(invokeToolNames))
+		cancelled   bool
 	)
 
 	msg.Status.InvokeToolNames = invokeToolNames
 
 	for i, toolName := range invokeToolNames {
-		var invoke v1.InvokeTool
-		if err := req.Get(&invoke, msg.Namespace, toolName); apierror.IsNotFound(err) {
+		if err := req.Get(&invokes[i], msg.Namespace, toolName); apierror.IsNotFound(err) {
 			// Ignore not found, it should be found later
 			return nil
 		} else if err != nil {
 			return err
 		}
+		if msg.Spec.Cancel && (len(invokes[i].Status.Content) == 0 ||
+			meta.IsStatusConditionTrue(invokes[i].Status.Conditions, v1.InvokeToolConditionCancelled)) {
+			cancelled = true
+		}
+	}
 
+	for i := range invokes {
+		invoke := &invokes[i]
+		toolName := invoke.Name
 		if len(invoke.Status.Content) == 0 {
 			continue
 		}
@@ -87,7 +99,8 @@ func InvokeTools(req router.Request, resp router.Response) error {
 					InProgress: invoke.Status.InProgress,
 				},
 				ParentMessageName: lastMessage,
-				More:              i != len(invokeToolNames)-1,
+				// The results of cancelled calls will never arrive, so don't ask for a completion
+				More: i != len(invokeToolNames)-1 || cancelled,
 			},
 		}
 		lastMessage = toolMessage.Name
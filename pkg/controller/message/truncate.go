@@ -0,0 +1,165 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	openai2 "github.com/acorn-io/assistant-runtime/pkg/openai"
+	"github.com/acorn-io/assistant-runtime/pkg/tokens"
+	"github.com/acorn-io/z"
+	"github.com/sashabaranov/go-openai"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	summaryMaxTokens = 512
+	summaryPrompt    = "Summarize the following conversation between a user and an assistant. Keep any facts, " +
+		"decisions, names and values that may be needed to continue the conversation. Respond with only the summary."
+)
+
+// toGroups splits history into the smallest runs of messages that can be dropped without leaving a tool
+// call without its results or a tool result without its call.
+func toGroups(history []v1.MessageBody) (result [][]v1.MessageBody) {
+	for _, msg := range history {
+		if msg.Role == v1.RoleTypeTool && len(result) > 0 && result[len(result)-1][0].IsToolCall() {
+			result[len(result)-1] = append(result[len(result)-1], msg)
+			continue
+		}
+		result = append(result, []v1.MessageBody{msg})
+	}
+	return
+}
+
+func flatten(groups [][]v1.MessageBody) (result []v1.MessageBody) {
+	for _, group := range groups {
+		result = append(result, group...)
+	}
+	return
+}
+
+// truncate returns the messages to send for request so that the prompt fits the context window of the model.
+// system messages are always sent. The number of messages sent and dropped is recorded on the status of msg.
+func (h *Handler) truncate(ctx context.Context, c kclient.Client, msg *v1.Message, request openai2.CompletionRequest,
+	policy v1.TruncationPolicy, system, history []v1.MessageBody) ([]v1.MessageBody, error) {
+	var (
+		groups = toGroups(history)
+		start  int
+		window = policy.ContextWindow
+	)
+
+	// The window and the tokens are those of the model the request is sent to, which is a default model of the
+	// provider if the assistant doesn't set one
+	model, err := h.oaiClient.Model(ctx, c, msg.Namespace, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if window <= 0 {
+		window = tokens.ContextWindow(model)
+	}
+
+	maxTokens := request.MaxToken
+	if maxTokens == 0 {
+		maxTokens = openai2.DefaultMaxTokens
+	}
+
+	budget := window - maxTokens - tokens.Tools(model, request.Tools) - tokens.Messages(model, system)
+	if policy.Strategy == v1.TruncationStrategySummarize {
+		budget -= summaryMaxTokens
+	}
+
+	if policy.Strategy == v1.TruncationStrategyKeepLast && policy.LastMessages > 0 {
+		start = len(groups)
+		for kept := 0; start > 0 && kept < policy.LastMessages; kept += len(groups[start]) {
+			start--
+		}
+	}
+
+	// The last group is always sent, if it doesn't fit the provider will say so.
+	for start < len(groups)-1 && tokens.Messages(model, flatten(groups[start:])) > budget {
+		start++
+	}
+
+	dropped := flatten(groups[:start])
+	result := append(append([]v1.MessageBody{}, system...), flatten(groups[start:])...)
+
+	if policy.Strategy == v1.TruncationStrategySummarize && len(dropped) > 0 {
+		summary, err := h.summarize(ctx, c, msg, request, model, window, dropped)
+		if err != nil {
+			return nil, err
+		}
+		result = append(append(append([]v1.MessageBody{}, system...), summary), flatten(groups[start:])...)
+	}
+
+	msg.Status.SentMessages = len(result)
+	msg.Status.DroppedMessages = len(dropped)
+	return result, nil
+}
+
+func (h *Handler) summarize(ctx context.Context, c kclient.Client, msg *v1.Message, request openai2.CompletionRequest, model string, window int, msgs []v1.MessageBody) (v1.MessageBody, error) {
+	transcript := toTranscript(msgs)
+
+	// The transcript itself must fit in the window, so cut the oldest part of it if it doesn't.
+	limit := window - summaryMaxTokens - tokens.Text(model, summaryPrompt) - 100
+	for len(transcript) > 0 && tokens.Text(model, transcript) > limit {
+		cut := len(transcript) / 10
+		if i := strings.IndexByte(transcript[cut:], '\n'); i >= 0 {
+			transcript = transcript[cut+i+1:]
+		} else {
+			transcript = ""
+		}
+	}
+
+	result, usage, err := h.oaiClient.Call(ctx, c, msg.Namespace, openai2.CompletionRequest{
+		Provider:    request.Provider,
+		Model:       request.Model,
+		MaxToken:    summaryMaxTokens,
+		Cache:       z.Pointer(true),
+		CacheLabels: request.CacheLabels,
+		Messages: []v1.MessageBody{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: v1.Text(summaryPrompt),
+			},
+			{
+				Role:    v1.RoleTypeUser,
+				Content: v1.Text(transcript),
+			},
+		},
+	}, nil)
+	if err != nil {
+		return v1.MessageBody{}, fmt.Errorf("summarizing %d messages: %w", len(msgs), err)
+	}
+	h.addUsage(msg, usage)
+
+	var texts []string
+	for _, content := range result.Content {
+		if content.Text != "" {
+			texts = append(texts, content.Text)
+		}
+	}
+
+	return v1.MessageBody{
+		Role:    openai.ChatMessageRoleSystem,
+		Content: v1.Text("Summary of the earlier conversation:\n" + strings.Join(texts, "\n")),
+	}, nil
+}
+
+func toTranscript(msgs []v1.MessageBody) string {
+	buf := strings.Builder{}
+	for _, msg := range msgs {
+		for _, content := range msg.Content {
+			switch {
+			case content.Text != "":
+				buf.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, content.Text))
+			case content.ToolCall != nil:
+				buf.WriteString(fmt.Sprintf("%s: called %s with %s\n", msg.Role, content.ToolCall.Function.Name, content.ToolCall.Function.Arguments))
+			case content.Image != nil:
+				buf.WriteString(fmt.Sprintf("%s: [image]\n", msg.Role))
+			}
+		}
+	}
+	return buf.String()
+}
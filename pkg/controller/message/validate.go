@@ -0,0 +1,77 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	openai2 "github.com/acorn-io/assistant-runtime/pkg/openai"
+	"github.com/acorn-io/assistant-runtime/pkg/schema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const repairPrompt = "Your response does not match the required JSON schema:\n%s\n\n" +
+	"Respond again with only a corrected JSON document."
+
+// validateResponse checks the completed message against the response schema of request. If it doesn't match,
+// the model is sent the errors and asked once to repair its response. A response that still doesn't match is
+// kept, and recorded as invalid in the ResponseValid condition.
+func (h *Handler) validateResponse(ctx context.Context, c kclient.Client, msg *v1.Message, request openai2.CompletionRequest) error {
+	var validationErr *schema.ValidationError
+
+	err := schema.Validate(request.ResponseSchema, responseText(msg.Status.Message))
+	if errors.As(err, &validationErr) {
+		repair := request
+		repair.Messages = append(slices.Clip(request.Messages), msg.Status.Message, v1.MessageBody{
+			Role:    v1.RoleTypeUser,
+			Content: v1.Text(fmt.Sprintf(repairPrompt, strings.Join(validationErr.Errors, "\n"))),
+		})
+		if err := h.complete(ctx, c, msg, repair); err != nil {
+			return err
+		}
+		err = schema.Validate(request.ResponseSchema, responseText(msg.Status.Message))
+	}
+
+	if errors.As(err, &validationErr) {
+		meta.SetStatusCondition(&msg.Status.Conditions, metav1.Condition{
+			Type:               v1.MessageConditionResponseValid,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: msg.Generation,
+			Reason:             "SchemaValidationFailed",
+			Message:            validationErr.Error(),
+		})
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	meta.SetStatusCondition(&msg.Status.Conditions, metav1.Condition{
+		Type:               v1.MessageConditionResponseValid,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: msg.Generation,
+		Reason:             "SchemaValidated",
+	})
+	return nil
+}
+
+// responseText returns the text of body, without the markdown code fence models often wrap JSON in
+func responseText(body v1.MessageBody) string {
+	var buf strings.Builder
+	for _, content := range body.Content {
+		buf.WriteString(content.Text)
+	}
+
+	text := strings.TrimSpace(buf.String())
+	if rest, ok := strings.CutPrefix(text, "```"); ok {
+		rest = strings.TrimPrefix(rest, "json")
+		if rest, ok = strings.CutSuffix(rest, "```"); ok {
+			text = strings.TrimSpace(rest)
+		}
+	}
+	return text
+}
@@ -3,6 +3,8 @@ package controller
 import (
 	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
 	"github.com/acorn-io/assistant-runtime/pkg/controller/appspec"
+	"github.com/acorn-io/assistant-runtime/pkg/controller/assistant"
+	"github.com/acorn-io/assistant-runtime/pkg/controller/cache"
 	"github.com/acorn-io/assistant-runtime/pkg/controller/invoketool"
 	"github.com/acorn-io/assistant-runtime/pkg/controller/message"
 	"github.com/acorn-io/assistant-runtime/pkg/controller/thread"
@@ -13,18 +15,33 @@ import (
 )
 
 func routes(router *router.Router, services *Services) error {
-	messageHandler := message.NewGenerateHandler(services.OpenAIClient)
+	messageHandler := message.NewGenerateHandler(services.Providers, services.Prices, services.Publisher)
+	summaryHandler := thread.NewSummaryHandler(services.Providers, services.Prices)
 
 	root := router.Middleware(conditions.ErrorMiddleware())
 	root.Type(&acornv1.App{}).Handler(&appspec.Handler{AppName: services.AppName})
 	root.Type(&v1.Message{}).HandlerFunc(message.Initialize)
+	root.Type(&v1.Message{}).HandlerFunc(message.Children)
 
 	withThread := root.Middleware(thread.IsSet)
 	withThread.Type(&v1.Message{}).HandlerFunc(message.InvokeTools)
 	withThread.Type(&v1.Message{}).HandlerFunc(messageHandler.CreateAssistantMessage)
 	withThread.Type(&v1.Message{}).HandlerFunc(messageHandler.CompleteAssistant)
+	root.Type(&v1.Message{}).HandlerFunc(message.Phase)
 
 	root.Type(&v1.InvokeTool{}).HandlerFunc(invoketool.Handle)
+	root.Type(&v1.InvokeTool{}).HandlerFunc(invoketool.Phase)
+
+	root.Type(&v1.Cache{}).Handler(&cache.GC{
+		TTL:     services.CacheTTL,
+		MaxSize: services.CacheSize,
+		Client:  router.Backend(),
+	})
+
+	root.Type(&v1.Thread{}).HandlerFunc(thread.Branch)
+	root.Type(&v1.Thread{}).HandlerFunc(thread.Usage)
+	root.Type(&v1.Thread{}).HandlerFunc(summaryHandler.Summarize)
+	root.Type(&v1.Assistant{}).HandlerFunc(assistant.Usage)
 
 	root.Type(&v1.InvokeTool{}).HandlerFunc(gc)
 	root.Type(&v1.Assistant{}).HandlerFunc(gc)
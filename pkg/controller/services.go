@@ -2,30 +2,76 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
 	assistant_acorn_io "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io"
-	"github.com/acorn-io/assistant-runtime/pkg/openai"
+	"github.com/acorn-io/assistant-runtime/pkg/controller/message"
+	"github.com/acorn-io/assistant-runtime/pkg/prices"
+	"github.com/acorn-io/assistant-runtime/pkg/providers"
 	"github.com/acorn-io/assistant-runtime/pkg/scheme"
 	"github.com/acorn-io/baaah"
 	"github.com/acorn-io/baaah/pkg/restconfig"
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/baaah/pkg/runtime"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type Services struct {
-	AppName      string
-	OpenAIClient *openai.Client
-	Router       *router.Router
-	PreStart     func(ctx context.Context) error
+	AppName   string
+	Providers *providers.Registry
+	Prices    prices.Table
+	Publisher *message.Publisher
+	CacheTTL  time.Duration
+	CacheSize int64
+	Router    *router.Router
+	PreStart  func(ctx context.Context) error
 }
 
 func NewServices(opt Options) (*Services, error) {
-	openAIClient, err := openai.NewClient()
+	registry, err := providers.FromEnv(opt.Provider)
 	if err != nil {
 		return nil, err
 	}
 
-	apiServerRESTConfig, err := restconfig.FromURLTokenAndScheme(opt.ApiUrl, opt.ApiToken, scheme.Scheme)
+	priceTable, err := prices.Load(opt.PriceTable)
+	if err != nil {
+		return nil, err
+	}
+
+	var cacheTTL time.Duration
+	if opt.CacheTTL != "" {
+		cacheTTL, err = time.ParseDuration(opt.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache TTL %q: %w", opt.CacheTTL, err)
+		}
+	}
+
+	var cacheSize int64
+	if opt.CacheSize != "" {
+		q, err := resource.ParseQuantity(opt.CacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache size %q: %w", opt.CacheSize, err)
+		}
+		cacheSize = q.Value()
+	}
+
+	apiToken, err := apiToken(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	apiServerRESTConfig, err := restconfig.FromURLTokenAndScheme(opt.ApiUrl, apiToken, scheme.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	publisher, err := message.NewPublisher(apiServerRESTConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -46,11 +92,33 @@ func NewServices(opt Options) (*Services, error) {
 	}
 
 	return &Services{
-		AppName:      opt.AppName,
-		OpenAIClient: openAIClient,
-		Router:       r,
+		AppName:   opt.AppName,
+		Providers: registry,
+		Prices:    priceTable,
+		Publisher: publisher,
+		CacheTTL:  cacheTTL,
+		CacheSize: cacheSize,
+		Router:    r,
 		PreStart: func(ctx context.Context) error {
 			return restconfig.WaitFor(ctx, apiServerRESTConfig)
 		},
 	}, nil
 }
+
+// apiToken returns the token passed to the controller, or the one in the token file. Without a token the content of
+// messages can't be published while it is generated, clients only get it once it is saved.
+func apiToken(opt Options) (string, error) {
+	if opt.ApiToken != "" || opt.ApiTokenFile == "" {
+		return opt.ApiToken, nil
+	}
+
+	data, err := os.ReadFile(opt.ApiTokenFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("no API token is passed and the token file doesn't exist, the content of messages won't be streamed",
+			"file", opt.ApiTokenFile)
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("reading API token: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,42 @@
+package thread
+
+import (
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Branch keeps the thread on the branch that contains spec.activeMessageName by pointing each message on the
+// way from the start of the thread to it at the next message on that way
+func Branch(req router.Request, resp router.Response) error {
+	thread := req.Object.(*v1.Thread)
+
+	if thread.Spec.ActiveMessageName == "" {
+		return nil
+	}
+
+	var msg v1.Message
+	if err := req.Get(&msg, thread.Namespace, thread.Spec.ActiveMessageName); apierror.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for msg.Name != thread.Spec.StartMessageName && msg.Spec.ParentMessageName != "" {
+		var parent v1.Message
+		if err := req.Get(&parent, thread.Namespace, msg.Spec.ParentMessageName); apierror.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if parent.Status.NextMessageName != msg.Name {
+			parent.Status.NextMessageName = msg.Name
+			if err := req.Client.Status().Update(req.Ctx, &parent); err != nil {
+				return err
+			}
+		}
+		msg = parent
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package thread
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/controller/message"
+	openai2 "github.com/acorn-io/assistant-runtime/pkg/openai"
+	"github.com/acorn-io/assistant-runtime/pkg/prices"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/z"
+	"github.com/sashabaranov/go-openai"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	titleMaxTokens  = 20
+	titleMaxLength  = 80
+	transcriptLimit = 2000
+	titleRetry      = 30 * time.Second
+	titlePrompt     = "Write a short title of at most six words for a conversation that starts with the following " +
+		"messages. Respond with only the title."
+)
+
+func NewSummaryHandler(c message.CompleteClient, prices prices.Table) *SummaryHandler {
+	return &SummaryHandler{
+		client: c,
+		prices: prices,
+	}
+}
+
+// SummaryHandler maintains the fields of the thread status that describe its messages: the title, the last
+// message, the number of messages and tool calls, and the phase.
+type SummaryHandler struct {
+	client message.CompleteClient
+	prices prices.Table
+}
+
+func (h *SummaryHandler) Summarize(req router.Request, resp router.Response) error {
+	thread := req.Object.(*v1.Thread)
+
+	msgs, err := messages(req, thread)
+	if err != nil {
+		return err
+	}
+
+	thread.Status.MessageCount = len(msgs)
+	thread.Status.ToolCallCount = 0
+	for _, msg := range msgs {
+		if msg.Status.Message.Role != v1.RoleTypeAssistant {
+			continue
+		}
+		for _, content := range msg.Status.Message.Content {
+			if content.ToolCall != nil {
+				thread.Status.ToolCallCount++
+			}
+		}
+	}
+
+	thread.Status.LastMessageName = ""
+	thread.Status.LastMessageTime = nil
+	thread.Status.Phase = v1.PhasePending
+	if len(msgs) > 0 {
+		last := msgs[len(msgs)-1]
+		thread.Status.LastMessageName = last.Name
+		thread.Status.LastMessageTime = z.Pointer(last.CreationTimestamp)
+		// A thread whose last message is complete but still waiting for a response is pending
+		if last.Status.Phase != v1.PhaseComplete || last.Status.Message.Role == v1.RoleTypeAssistant {
+			thread.Status.Phase = last.Status.Phase
+		}
+	}
+
+	if thread.Status.Description == "" {
+		return h.describe(req, resp, thread, msgs)
+	}
+	return nil
+}
+
+// describe sets the description of thread to a title generated from its first question and answer. Threads of
+// assistants called by other assistants are described by their input instead, as nobody picks them by title.
+func (h *SummaryHandler) describe(req router.Request, resp router.Response, thread *v1.Thread, msgs []v1.Message) error {
+	var (
+		transcript []v1.MessageBody
+		input      string
+		answered   bool
+	)
+
+	for _, msg := range msgs {
+		text := textOf(msg.Status.Message)
+		if text == "" || msg.Status.Message.Role == v1.RoleTypeTool {
+			continue
+		}
+		if input == "" && msg.Status.Message.Role == v1.RoleTypeUser {
+			input = text
+		}
+		transcript = append(transcript, v1.MessageBody{
+			Role:    msg.Status.Message.Role,
+			Content: v1.Text(truncate(text, transcriptLimit)),
+		})
+		if msg.Status.Message.Role == v1.RoleTypeAssistant && msg.Status.Phase == v1.PhaseComplete {
+			answered = true
+			break
+		}
+	}
+
+	if input == "" || !answered {
+		return nil
+	}
+
+	if thread.Spec.ParentThreadName != "" {
+		thread.Status.Description = title(input)
+		return nil
+	}
+
+	var assistant v1.Assistant
+	if err := req.Get(&assistant, thread.Namespace, thread.Spec.AssistantName); err != nil {
+		return err
+	}
+
+	result, usage, err := h.client.Call(req.Ctx, req.Client, thread.Namespace, openai2.CompletionRequest{
+		Provider: assistant.Spec.Provider,
+		Model:    assistant.Spec.Model,
+		MaxToken: titleMaxTokens,
+		Cache:    z.Pointer(true),
+		CacheLabels: map[string]string{
+			v1.CacheAssistantLabel: assistant.Name,
+			v1.CacheThreadLabel:    thread.Name,
+		},
+		Messages: append([]v1.MessageBody{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: v1.Text(titlePrompt),
+			},
+		}, transcript...),
+	}, nil)
+	if openai2.IsTransient(err) {
+		resp.RetryAfter(titleRetry)
+		return nil
+	} else if err != nil {
+		// A title is not worth failing the thread for, so fall back to the input
+		slog.Error("failed to generate thread title", "thread", thread.Name, "err", err)
+		thread.Status.Description = title(input)
+		return nil
+	}
+
+	usage.Cost = h.prices.Cost(usage)
+	thread.Status.DescriptionUsage = &usage
+	thread.Status.Description = title(textOf(*result))
+	if thread.Status.Description == "" {
+		thread.Status.Description = title(input)
+	}
+	return nil
+}
+
+// messages returns the messages of thread in order
+func messages(req router.Request, thread *v1.Thread) (result []v1.Message, _ error) {
+	next := thread.Spec.StartMessageName
+	for next != "" {
+		var msg v1.Message
+		if err := req.Get(&msg, thread.Namespace, next); apierror.IsNotFound(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, msg)
+		next = msg.Status.NextMessageName
+	}
+	return result, nil
+}
+
+func textOf(body v1.MessageBody) string {
+	var texts []string
+	for _, content := range body.Content {
+		if content.Text != "" {
+			texts = append(texts, content.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// title cleans up a generated title, or cuts a message down to one
+func title(text string) string {
+	text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	text = strings.TrimPrefix(text, "Title:")
+	text = strings.Trim(text, " \t\"'*#")
+	return truncate(text, titleMaxLength)
+}
+
+func truncate(text string, length int) string {
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+	return string([]rune(text)[:length-3]) + "..."
+}
@@ -0,0 +1,42 @@
+package thread
+
+import (
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"k8s.io/apimachinery/pkg/fields"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Usage totals the token usage of all the messages in the thread and of the call that generated its description
+func Usage(req router.Request, resp router.Response) error {
+	var (
+		thread = req.Object.(*v1.Thread)
+		msgs   v1.MessageList
+		totals v1.UsageTotals
+	)
+
+	if err := req.List(&msgs, &kclient.ListOptions{
+		Namespace:     thread.Namespace,
+		FieldSelector: fields.OneTermEqualSelector(v1.MessageThreadNameField, thread.Name),
+	}); err != nil {
+		return err
+	}
+
+	for _, msg := range msgs.Items {
+		if msg.Status.ThreadName != thread.Name || msg.Status.Usage == nil {
+			continue
+		}
+		totals.Add(*msg.Status.Usage)
+	}
+
+	if thread.Status.DescriptionUsage != nil {
+		totals.Add(*thread.Status.DescriptionUsage)
+	}
+
+	if totals == (v1.UsageTotals{}) {
+		thread.Status.Usage = nil
+	} else {
+		thread.Status.Usage = &totals
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package fakellm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/acorn-io/aml"
+	"github.com/sashabaranov/go-openai"
+)
+
+// Script is the set of rules used to answer requests. The first rule that matches the last message of a
+// request is used. If no rule matches, the last message is echoed back.
+type Script struct {
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Rule matches the last message of a request and describes the response to it
+type Rule struct {
+	// Match is a regular expression matched against the text of the last message. Groups in the expression
+	// can be used in Content as $1, ${name} and so on.
+	Match string `json:"match,omitempty"`
+	// Role is the role of the last message, such as user or tool
+	Role string `json:"role,omitempty"`
+	// Tool is the name of the function whose result is the last message
+	Tool string `json:"tool,omitempty"`
+	// Model is a regular expression matched against the requested model
+	Model string `json:"model,omitempty"`
+
+	// Echo responds with the text of the last message
+	Echo      bool       `json:"echo,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	ToolCalls []ToolCall `json:"toolCalls,omitempty"`
+	// Error responds with an error status instead of a completion
+	Error *Error `json:"error,omitempty"`
+	// Delay is the time to wait before each word of a streamed response, such as 100ms
+	Delay string `json:"delay,omitempty"`
+
+	match *regexp.Regexp
+	model *regexp.Regexp
+	delay time.Duration
+}
+
+type ToolCall struct {
+	Name string `json:"name"`
+	// Arguments is either a JSON object or a string containing JSON
+	Arguments any `json:"arguments,omitempty"`
+}
+
+type Error struct {
+	Status     int    `json:"status,omitempty"`
+	Message    string `json:"message,omitempty"`
+	RetryAfter int    `json:"retryAfter,omitempty"`
+}
+
+// LoadScript reads a script from a YAML, JSON or AML file
+func LoadScript(file string) (*Script, error) {
+	script := &Script{}
+	if file != "" {
+		if err := aml.UnmarshalFile(file, script); err != nil {
+			return nil, err
+		}
+	}
+	return script, script.compile()
+}
+
+func (s *Script) compile() (err error) {
+	for i := range s.Rules {
+		rule := &s.Rules[i]
+		if rule.Match != "" {
+			rule.match, err = regexp.Compile(rule.Match)
+			if err != nil {
+				return fmt.Errorf("rule %d: invalid match: %w", i, err)
+			}
+		}
+		if rule.Delay != "" {
+			rule.delay, err = time.ParseDuration(rule.Delay)
+			if err != nil {
+				return fmt.Errorf("rule %d: invalid delay: %w", i, err)
+			}
+		}
+		if rule.Model != "" {
+			rule.model, err = regexp.Compile(rule.Model)
+			if err != nil {
+				return fmt.Errorf("rule %d: invalid model: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// response is the result of applying a rule to a request
+type response struct {
+	Content   string
+	ToolCalls []openai.ToolCall
+	Error     *Error
+	Delay     time.Duration
+}
+
+func (s *Script) respond(request openai.ChatCompletionRequest) (response, error) {
+	var last openai.ChatCompletionMessage
+	if len(request.Messages) > 0 {
+		last = request.Messages[len(request.Messages)-1]
+	}
+	text := messageText(last)
+	tool := toolName(request.Messages, last)
+
+	for _, rule := range s.Rules {
+		if rule.Role != "" && rule.Role != last.Role {
+			continue
+		}
+		if rule.Tool != "" && rule.Tool != tool {
+			continue
+		}
+		if rule.model != nil && !rule.model.MatchString(request.Model) {
+			continue
+		}
+		var groups []int
+		if rule.match != nil {
+			groups = rule.match.FindStringSubmatchIndex(text)
+			if groups == nil {
+				continue
+			}
+		}
+		return rule.apply(text, groups, len(request.Messages))
+	}
+
+	return response{
+		Content: text,
+	}, nil
+}
+
+func (r Rule) apply(text string, groups []int, messages int) (response, error) {
+	result := response{
+		Error: r.Error,
+		Delay: r.delay,
+	}
+
+	switch {
+	case r.Echo:
+		result.Content = text
+	case r.match != nil:
+		result.Content = string(r.match.ExpandString(nil, r.Content, text, groups))
+	default:
+		result.Content = r.Content
+	}
+
+	for i, call := range r.ToolCalls {
+		args, ok := call.Arguments.(string)
+		if !ok {
+			if call.Arguments == nil {
+				call.Arguments = map[string]any{}
+			}
+			data, err := json.Marshal(call.Arguments)
+			if err != nil {
+				return result, err
+			}
+			args = string(data)
+		}
+		result.ToolCalls = append(result.ToolCalls, openai.ToolCall{
+			ID:   fmt.Sprintf("call_fake_%d_%d", messages, i),
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionCall{
+				Name:      call.Name,
+				Arguments: args,
+			},
+		})
+	}
+
+	return result, nil
+}
+
+func messageText(msg openai.ChatCompletionMessage) string {
+	if len(msg.MultiContent) == 0 {
+		return msg.Content
+	}
+	var texts []string
+	for _, part := range msg.MultiContent {
+		if part.Type == openai.ChatMessagePartTypeText {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// toolName returns the name of the function that was called to produce the tool result msg
+func toolName(msgs []openai.ChatCompletionMessage, msg openai.ChatCompletionMessage) string {
+	if msg.ToolCallID == "" {
+		return ""
+	}
+	for _, m := range msgs {
+		for _, call := range m.ToolCalls {
+			if call.ID == msg.ToolCallID {
+				return call.Function.Name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package fakellm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func loadScript(t *testing.T, content string) (*Script, error) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "script.yaml")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadScript(file)
+}
+
+func TestDelay(t *testing.T) {
+	script, err := loadScript(t, `
+rules:
+- match: slow
+  content: one two three
+  delay: 50ms
+- echo: true
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for text, want := range map[string]time.Duration{
+		"slow please": 50 * time.Millisecond,
+		"fast please": 0,
+	} {
+		resp, err := script.respond(openai.ChatCompletionRequest{
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: text,
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Delay != want {
+			t.Errorf("delay of %q = %s, want %s", text, resp.Delay, want)
+		}
+	}
+}
+
+func TestInvalidDelay(t *testing.T) {
+	if _, err := loadScript(t, `
+rules:
+- content: hi
+  delay: soon
+`); err == nil {
+		t.Fatal("expected an error for an invalid delay")
+	}
+}
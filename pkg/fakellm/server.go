@@ -0,0 +1,227 @@
+package fakellm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acorn-io/assistant-runtime/pkg/hash"
+	"github.com/sashabaranov/go-openai"
+)
+
+// argumentChunkSize is the number of characters of tool call arguments sent in each chunk, so that clients
+// have to assemble arguments the same way they do for real providers
+const argumentChunkSize = 16
+
+// Server is an OpenAI compatible chat completions API that answers from a Script
+type Server struct {
+	script *Script
+}
+
+func NewServer(script *Script) *Server {
+	return &Server{
+		script: script,
+	}
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/chat/completions"):
+		s.chatCompletions(rw, req)
+	case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/models"):
+		writeJSON(rw, http.StatusOK, openai.ModelsList{
+			Models: []openai.Model{
+				{
+					ID:      "fake",
+					Object:  "model",
+					OwnedBy: "fake-llm",
+				},
+			},
+		})
+	default:
+		writeError(rw, &Error{
+			Status:  http.StatusNotFound,
+			Message: fmt.Sprintf("%s %s not found", req.Method, req.URL.Path),
+		})
+	}
+}
+
+func (s *Server) chatCompletions(rw http.ResponseWriter, req *http.Request) {
+	var request openai.ChatCompletionRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		writeError(rw, &Error{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	resp, err := s.script.respond(request)
+	if err != nil {
+		writeError(rw, &Error{
+			Status:  http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	slog.Info("chat completion", "model", request.Model, "messages", len(request.Messages), "stream", request.Stream,
+		"content", resp.Content, "toolCalls", len(resp.ToolCalls))
+
+	if resp.Error != nil {
+		writeError(rw, resp.Error)
+		return
+	}
+
+	var (
+		id      = "chatcmpl-" + hash.Encode(request)[:24]
+		created = time.Now().Unix()
+		finish  = openai.FinishReasonStop
+	)
+	if len(resp.ToolCalls) > 0 {
+		finish = openai.FinishReasonToolCalls
+	}
+
+	if !request.Stream {
+		writeJSON(rw, http.StatusOK, openai.ChatCompletionResponse{
+			ID:      id,
+			Object:  "chat.completion",
+			Created: created,
+			Model:   request.Model,
+			Choices: []openai.ChatCompletionChoice{
+				{
+					Message: openai.ChatCompletionMessage{
+						Role:      openai.ChatMessageRoleAssistant,
+						Content:   resp.Content,
+						ToolCalls: resp.ToolCalls,
+					},
+					FinishReason: finish,
+				},
+			},
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.WriteHeader(http.StatusOK)
+
+	send := func(delta openai.ChatCompletionStreamChoiceDelta, finishReason openai.FinishReason) {
+		data, _ := json.Marshal(openai.ChatCompletionStreamResponse{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   request.Model,
+			Choices: []openai.ChatCompletionStreamChoice{
+				{
+					Delta:        delta,
+					FinishReason: finishReason,
+				},
+			},
+		})
+		_, _ = fmt.Fprintf(rw, "data: %s\n\n", data)
+		if f, ok := rw.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	send(openai.ChatCompletionStreamChoiceDelta{
+		Role: openai.ChatMessageRoleAssistant,
+	}, "")
+
+	for _, word := range strings.SplitAfter(resp.Content, " ") {
+		if word != "" {
+			if !sleep(req.Context(), resp.Delay) {
+				// The client went away
+				return
+			}
+			send(openai.ChatCompletionStreamChoiceDelta{
+				Content: word,
+			}, "")
+		}
+	}
+
+	for i, call := range resp.ToolCalls {
+		index := i
+		send(openai.ChatCompletionStreamChoiceDelta{
+			ToolCalls: []openai.ToolCall{
+				{
+					Index: &index,
+					ID:    call.ID,
+					Type:  call.Type,
+					Function: openai.FunctionCall{
+						Name: call.Function.Name,
+					},
+				},
+			},
+		}, "")
+		for args := call.Function.Arguments; args != ""; {
+			chunk := args[:min(len(args), argumentChunkSize)]
+			args = args[len(chunk):]
+			send(openai.ChatCompletionStreamChoiceDelta{
+				ToolCalls: []openai.ToolCall{
+					{
+						Index: &index,
+						Function: openai.FunctionCall{
+							Arguments: chunk,
+						},
+					},
+				},
+			}, "")
+		}
+	}
+
+	send(openai.ChatCompletionStreamChoiceDelta{}, finish)
+	_, _ = fmt.Fprint(rw, "data: [DONE]\n\n")
+}
+
+// sleep waits for d or until ctx is done, and returns false if ctx is done
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func writeJSON(rw http.ResponseWriter, status int, obj any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(obj)
+}
+
+func writeError(rw http.ResponseWriter, e *Error) {
+	status := e.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	if e.RetryAfter > 0 {
+		rw.Header().Set("Retry-After", strconv.Itoa(e.RetryAfter))
+	}
+
+	errType := "server_error"
+	switch {
+	case status == http.StatusTooManyRequests:
+		errType = "rate_limit_error"
+	case status < http.StatusInternalServerError:
+		errType = "invalid_request_error"
+	}
+
+	writeJSON(rw, status, map[string]any{
+		"error": map[string]any{
+			"message": e.Message,
+			"type":    errType,
+		},
+	})
+}
@@ -0,0 +1,57 @@
+package fakellm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamStopsWhenCancelled(t *testing.T) {
+	script, err := loadScript(t, `
+rules:
+- content: one two three four five six seven eight nine ten
+  delay: 200ms
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		handlerDone = make(chan struct{})
+		server      = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			defer close(handlerDone)
+			NewServer(script).ServeHTTP(rw, req)
+		}))
+	)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1/chat/completions",
+		strings.NewReader(`{"model":"fake","stream":true,"messages":[{"role":"user","content":"hi"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// The role is sent before the first delay
+	if _, err := resp.Body.Read(make([]byte, 1)); err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	cancel()
+
+	select {
+	case <-handlerDone:
+	case <-time.After(time.Second):
+		t.Fatal("stream kept sleeping after the request was cancelled")
+	}
+}
@@ -0,0 +1,271 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/acorn-io/assistant-runtime/pkg/hash"
+	openai2 "github.com/acorn-io/assistant-runtime/pkg/openai"
+	"github.com/sashabaranov/go-openai"
+)
+
+type Backend struct {
+	config Config
+}
+
+func NewBackend(cfg Config) *Backend {
+	return &Backend{
+		config: cfg,
+	}
+}
+
+type chatRequest struct {
+	Model    string         `json:"model"`
+	Messages []chatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Format   string         `json:"format,omitempty"`
+	Tools    []openai.Tool  `json:"tools,omitempty"`
+	Options  map[string]any `json:"options,omitempty"`
+}
+
+type chatMessage struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	Images    []string   `json:"images,omitempty"`
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+}
+
+type toolCall struct {
+	Function toolCallFunction `json:"function"`
+}
+
+type toolCallFunction struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type chatResponse struct {
+	Model     string      `json:"model"`
+	CreatedAt string      `json:"created_at"`
+	Message   chatMessage `json:"message"`
+	Done      bool        `json:"done"`
+	Error     string      `json:"error,omitempty"`
+
+	PromptEvalCount int `json:"prompt_eval_count,omitempty"`
+	EvalCount       int `json:"eval_count,omitempty"`
+}
+
+func (b *Backend) CreateChatCompletionStream(ctx context.Context, request openai.ChatCompletionRequest) (openai2.Stream, error) {
+	body, err := json.Marshal(toRequest(request))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(b.config.BaseURL, "/")+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range b.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, openai2.WithRetryAfter(toError(resp), openai2.ParseRetryAfter(resp.Header))
+	}
+
+	return &stream{
+		body:    resp.Body,
+		scanner: bufio.NewScanner(resp.Body),
+	}, nil
+}
+
+func toError(resp *http.Response) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var errResp chatResponse
+	if err := json.Unmarshal(data, &errResp); err != nil || errResp.Error == "" {
+		return &openai.RequestError{
+			HTTPStatusCode: resp.StatusCode,
+			Err:            fmt.Errorf("%s", data),
+		}
+	}
+	return &openai.APIError{
+		Message:        errResp.Error,
+		HTTPStatusCode: resp.StatusCode,
+	}
+}
+
+func toRequest(in openai.ChatCompletionRequest) chatRequest {
+	out := chatRequest{
+		Model:  in.Model,
+		Stream: true,
+		Tools:  in.Tools,
+		Options: map[string]any{
+			"num_predict": in.MaxTokens,
+		},
+	}
+
+	if in.Seed != nil {
+		out.Options["seed"] = *in.Seed
+	}
+
+	if in.ResponseFormat != nil && (in.ResponseFormat.Type == openai.ChatCompletionResponseFormatTypeJSONObject ||
+		in.ResponseFormat.Type == openai.ChatCompletionResponseFormatTypeJSONSchema) {
+		out.Format = "json"
+	}
+
+	if instructions := openai2.SchemaInstructions(in.ResponseFormat); instructions != "" {
+		out.Messages = append(out.Messages, chatMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: instructions,
+		})
+	}
+
+	for _, msg := range in.Messages {
+		chatMsg := chatMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+
+		for _, part := range msg.MultiContent {
+			switch part.Type {
+			case openai.ChatMessagePartTypeText:
+				if chatMsg.Content != "" {
+					chatMsg.Content += "\n"
+				}
+				chatMsg.Content += part.Text
+			case openai.ChatMessagePartTypeImageURL:
+				if part.ImageURL == nil {
+					continue
+				}
+				if _, data, ok := strings.Cut(part.ImageURL.URL, ";base64,"); ok {
+					chatMsg.Images = append(chatMsg.Images, data)
+				} else {
+					chatMsg.Content += fmt.Sprintf("Image URL %s", part.ImageURL.URL)
+				}
+			}
+		}
+
+		for _, call := range msg.ToolCalls {
+			args := json.RawMessage(call.Function.Arguments)
+			if !json.Valid(args) {
+				args = json.RawMessage("{}")
+			}
+			chatMsg.ToolCalls = append(chatMsg.ToolCalls, toolCall{
+				Function: toolCallFunction{
+					Name:      call.Function.Name,
+					Arguments: args,
+				},
+			})
+		}
+
+		out.Messages = append(out.Messages, chatMsg)
+	}
+
+	return out
+}
+
+type stream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	started bool
+	tools   int
+	usage   *openai.Usage
+}
+
+func (s *stream) Close() {
+	_ = s.body.Close()
+}
+
+func (s *stream) Usage() (openai.Usage, bool) {
+	if s.usage == nil {
+		return openai.Usage{}, false
+	}
+	return *s.usage, true
+}
+
+func (s *stream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	for s.scanner.Scan() {
+		line := bytes.TrimSpace(s.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var resp chatResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			return openai.ChatCompletionStreamResponse{}, err
+		}
+
+		if resp.Error != "" {
+			return openai.ChatCompletionStreamResponse{}, &openai.APIError{
+				Message: resp.Error,
+			}
+		}
+
+		if resp.Done {
+			s.usage = &openai.Usage{
+				PromptTokens:     resp.PromptEvalCount,
+				CompletionTokens: resp.EvalCount,
+				TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+			}
+		}
+
+		if resp.Done && resp.Message.Content == "" && len(resp.Message.ToolCalls) == 0 {
+			return openai.ChatCompletionStreamResponse{}, io.EOF
+		}
+
+		delta := openai.ChatCompletionStreamChoiceDelta{
+			Content: resp.Message.Content,
+		}
+		if !s.started {
+			delta.Role = openai.ChatMessageRoleAssistant
+			s.started = true
+		}
+
+		for _, call := range resp.Message.ToolCalls {
+			idx := s.tools
+			s.tools++
+			delta.ToolCalls = append(delta.ToolCalls, openai.ToolCall{
+				Index: &idx,
+				// Ollama does not assign IDs to tool calls, but they are needed to correlate the results.
+				ID:   "call_" + hash.Encode(map[string]any{"created": resp.CreatedAt, "index": idx, "call": call})[:24],
+				Type: openai.ToolTypeFunction,
+				Function: openai.FunctionCall{
+					Name:      call.Function.Name,
+					Arguments: string(call.Function.Arguments),
+				},
+			})
+		}
+
+		return openai.ChatCompletionStreamResponse{
+			Object: "chat.completion.chunk",
+			Model:  resp.Model,
+			Choices: []openai.ChatCompletionStreamChoice{
+				{
+					Delta: delta,
+				},
+			},
+		}, nil
+	}
+
+	if err := s.scanner.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return openai.ChatCompletionStreamResponse{}, err
+	}
+	return openai.ChatCompletionStreamResponse{}, io.EOF
+}
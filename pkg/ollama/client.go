@@ -0,0 +1,36 @@
+package ollama
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/acorn-io/assistant-runtime/pkg/openai"
+)
+
+const (
+	DefaultModel       = "llama2"
+	DefaultVisionModel = "llava"
+)
+
+var (
+	url = os.Getenv("OLLAMA_URL")
+)
+
+type Config struct {
+	BaseURL string
+	Headers map[string]string
+}
+
+// Configured returns true if the environment has the URL of an Ollama server
+func Configured() bool {
+	return url != ""
+}
+
+func NewClient() (*openai.Client, error) {
+	if url == "" {
+		return nil, fmt.Errorf("OLLAMA_URL env var is not set")
+	}
+	return openai.NewClientWithBackend(NewBackend(Config{
+		BaseURL: url,
+	}), DefaultModel, DefaultVisionModel), nil
+}
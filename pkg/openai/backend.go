@@ -0,0 +1,168 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Stream is a stream of chat completion chunks in the OpenAI wire format
+type Stream interface {
+	Recv() (openai.ChatCompletionStreamResponse, error)
+	Close()
+}
+
+// UsageStream is implemented by streams of providers that report token usage. Usage is only valid once
+// Recv has returned io.EOF.
+type UsageStream interface {
+	Usage() (openai.Usage, bool)
+}
+
+// Backend is the raw streaming API of a model provider. Providers that don't speak the OpenAI
+// protocol translate their requests and responses to the OpenAI format so that caching and
+// message assembly in Client is shared by all providers.
+type Backend interface {
+	CreateChatCompletionStream(ctx context.Context, request openai.ChatCompletionRequest) (Stream, error)
+}
+
+// SchemaInstructions returns a system prompt describing the JSON schema of format, for backends of providers
+// that can't enforce a schema. An empty string is returned if format has no schema.
+func SchemaInstructions(format *openai.ChatCompletionResponseFormat) string {
+	if format == nil || format.Type != openai.ChatCompletionResponseFormatTypeJSONSchema || format.JSONSchema == nil {
+		return ""
+	}
+	data, err := format.JSONSchema.Schema.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("Respond with only a JSON document, with no other text, that matches this JSON schema:\n%s", data)
+}
+
+// jsonSchemaModels are the models known to accept a json_schema response format, by exact name or by prefix
+// for the names ending with "-". Other models get a json_object response format with the schema as instructions.
+var jsonSchemaModels = []string{
+	"gpt-4o",
+	"gpt-4o-mini",
+	"gpt-4o-mini-",
+	"gpt-4o-2024-08-06",
+	"gpt-4o-2024-11-20",
+	"gpt-4.1",
+	"gpt-4.1-",
+	"o1",
+	"o1-2024-12-17",
+	"o3-mini",
+	"o3-mini-",
+}
+
+// supportsJSONSchema is true if the model is known to accept a json_schema response format
+func supportsJSONSchema(model string) bool {
+	for _, name := range jsonSchemaModels {
+		if model == name || strings.HasSuffix(name, "-") && strings.HasPrefix(model, name) {
+			return true
+		}
+	}
+	return false
+}
+
+type Config struct {
+	BaseURL string
+	APIKey  string
+	Headers map[string]string
+}
+
+func NewBackend(cfg Config) Backend {
+	oaiConfig := openai.DefaultConfig(cfg.APIKey)
+	if cfg.BaseURL != "" {
+		oaiConfig.BaseURL = cfg.BaseURL
+	}
+	transport := &retryAfterTransport{}
+	if len(cfg.Headers) > 0 {
+		transport.next = &HeaderTransport{
+			Headers: cfg.Headers,
+		}
+	}
+	oaiConfig.HTTPClient = &http.Client{
+		Transport: transport,
+	}
+	return &openAIBackend{
+		c: openai.NewClientWithConfig(oaiConfig),
+	}
+}
+
+type openAIBackend struct {
+	c *openai.Client
+}
+
+func (o *openAIBackend) CreateChatCompletionStream(ctx context.Context, request openai.ChatCompletionRequest) (Stream, error) {
+	var retryAfter time.Duration
+	if request.ResponseFormat != nil && request.ResponseFormat.Type == openai.ChatCompletionResponseFormatTypeJSONSchema &&
+		!supportsJSONSchema(request.Model) {
+		// The schema is validated after the completion instead
+		request.Messages = append([]openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: SchemaInstructions(request.ResponseFormat),
+			},
+		}, request.Messages...)
+		request.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+	// The usage of the whole request is sent in a last chunk with no choices
+	request.StreamOptions = &openai.StreamOptions{
+		IncludeUsage: true,
+	}
+	stream, err := o.c.CreateChatCompletionStream(context.WithValue(ctx, retryAfterKey{}, &retryAfter), request)
+	if err != nil {
+		return nil, WithRetryAfter(err, retryAfter)
+	}
+	return &openAIStream{
+		ChatCompletionStream: stream,
+	}, nil
+}
+
+type openAIStream struct {
+	*openai.ChatCompletionStream
+	usage *openai.Usage
+}
+
+func (o *openAIStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	response, err := o.ChatCompletionStream.Recv()
+	if err == nil && response.Usage != nil {
+		o.usage = response.Usage
+	}
+	return response, err
+}
+
+func (o *openAIStream) Usage() (openai.Usage, bool) {
+	if o.usage == nil {
+		return openai.Usage{}, false
+	}
+	return *o.usage, true
+}
+
+func (o *openAIStream) Close() {
+	_ = o.ChatCompletionStream.Close()
+}
+
+// HeaderTransport adds static headers to every request
+type HeaderTransport struct {
+	Headers map[string]string
+	Next    http.RoundTripper
+}
+
+func (h *HeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+	next := h.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
@@ -0,0 +1,76 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestSupportsJSONSchema(t *testing.T) {
+	for model, want := range map[string]bool{
+		"gpt-4o":                 true,
+		"gpt-4o-2024-08-06":      true,
+		"gpt-4o-2024-05-13":      false,
+		"gpt-4o-mini":            true,
+		"gpt-4o-mini-2024-07-18": true,
+		"gpt-4.1-nano":           true,
+		"gpt-4-turbo-preview":    false,
+		"gpt-4-vision-preview":   false,
+		"gpt-3.5-turbo":          false,
+		"llama3":                 false,
+	} {
+		if got := supportsJSONSchema(model); got != want {
+			t.Errorf("supportsJSONSchema(%q) = %v, want %v", model, got, want)
+		}
+	}
+}
+
+func TestJSONSchemaFallback(t *testing.T) {
+	for model, wantType := range map[string]openai.ChatCompletionResponseFormatType{
+		"gpt-4o":              openai.ChatCompletionResponseFormatTypeJSONSchema,
+		"gpt-4-turbo-preview": openai.ChatCompletionResponseFormatTypeJSONObject,
+	} {
+		var sent struct {
+			Messages       []openai.ChatCompletionMessage `json:"messages"`
+			ResponseFormat *struct {
+				Type openai.ChatCompletionResponseFormatType `json:"type"`
+			} `json:"response_format"`
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if err := json.NewDecoder(req.Body).Decode(&sent); err != nil {
+				t.Error(err)
+			}
+			rw.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(rw, "data: [DONE]\n\n")
+		}))
+
+		stream, err := NewBackend(Config{BaseURL: server.URL}).CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+			Model:    model,
+			Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+			ResponseFormat: &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+				JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+					Name:   "response",
+					Schema: jsonSchema{"type": "object"},
+				},
+			},
+		})
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream.Close()
+
+		if sent.ResponseFormat == nil || sent.ResponseFormat.Type != wantType {
+			t.Errorf("%s: response format %v, want %s", model, sent.ResponseFormat, wantType)
+		}
+		if hasInstructions := sent.Messages[0].Role == openai.ChatMessageRoleSystem; hasInstructions != (wantType == openai.ChatCompletionResponseFormatTypeJSONObject) {
+			t.Errorf("%s: schema instructions sent = %v", model, hasInstructions)
+		}
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/acorn-io/aml/pkg/jsonschema"
 	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
 	"github.com/acorn-io/assistant-runtime/pkg/hash"
+	"github.com/acorn-io/assistant-runtime/pkg/schema"
+	"github.com/acorn-io/assistant-runtime/pkg/tokens"
 	"github.com/acorn-io/assistant-runtime/pkg/vision"
 	"github.com/acorn-io/baaah/pkg/router"
 	"github.com/acorn-io/z"
@@ -34,46 +36,83 @@ var (
 )
 
 type Client struct {
-	c *openai.Client
+	c                  Backend
+	defaultModel       string
+	defaultVisionModel string
+}
+
+// Configured returns true if the environment has credentials or a URL for an OpenAI compatible API
+func Configured() bool {
+	return key != "" || url != ""
 }
 
 func NewClient() (*Client, error) {
-	if url == "" {
-		if key == "" {
-			return nil, fmt.Errorf("OPENAI_API_KEY env var is not set")
-		}
-		return &Client{
-			c: openai.NewClient(key),
-		}, nil
+	if url == "" && key == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY env var is not set")
 	}
 
-	cfg := openai.DefaultConfig(key)
-	cfg.BaseURL = url
+	return NewClientWithBackend(NewBackend(Config{
+		BaseURL: url,
+		APIKey:  key,
+	}), DefaultModel, DefaultVisionModel), nil
+}
+
+// NewClientWithBackend returns a Client that sends requests to the given backend, using defaultModel
+// or defaultVisionModel if the request does not specify a model.
+func NewClientWithBackend(backend Backend, defaultModel, defaultVisionModel string) *Client {
 	return &Client{
-		c: openai.NewClientWithConfig(cfg),
-	}, nil
+		c:                  backend,
+		defaultModel:       defaultModel,
+		defaultVisionModel: defaultVisionModel,
+	}
+}
+
+// NewCacheOnlyClient returns a client that only answers from cached responses, such as caches imported to replay
+// conversations with no model provider, and fails with err when a response is not cached. The cache key covers
+// the model, so assistants that don't set one are replayed with the default models of OpenAI.
+func NewCacheOnlyClient(err error) *Client {
+	return NewClientWithBackend(cacheOnlyBackend{err: err}, DefaultModel, DefaultVisionModel)
+}
+
+type cacheOnlyBackend struct {
+	err error
+}
+
+func (c cacheOnlyBackend) CreateChatCompletionStream(context.Context, openai.ChatCompletionRequest) (Stream, error) {
+	return nil, c.err
 }
 
 func (c *Client) cacheKey(request openai.ChatCompletionRequest) string {
 	return hash.Encode(request)
 }
 
-func (c *Client) fromCache(ctx context.Context, k8s kclient.Client, namespace string, messageRequest CompletionRequest, request openai.ChatCompletionRequest) (result []openai.ChatCompletionStreamResponse, _ bool, _ error) {
+func (c *Client) fromCache(ctx context.Context, k8s kclient.Client, namespace string, messageRequest CompletionRequest, request openai.ChatCompletionRequest) (result []openai.ChatCompletionStreamResponse, usage *v1.Usage, _ bool, _ error) {
 	if !z.Dereference(messageRequest.Cache) {
-		return nil, false, nil
+		return nil, nil, false, nil
 	}
 
 	var cache v1.Cache
 	if err := k8s.Get(ctx, router.Key(namespace, c.cacheKey(request)), &cache); apierrors.IsNotFound(err) {
-		return nil, false, nil
+		return nil, nil, false, nil
 	} else if err != nil {
-		return nil, false, err
+		return nil, nil, false, err
 	}
 	gz, err := gzip.NewReader(bytes.NewReader(cache.Content))
 	if err != nil {
-		return nil, false, err
+		return nil, nil, false, err
+	}
+	if err := json.NewDecoder(gz).Decode(&result); err != nil {
+		return nil, nil, false, err
+	}
+
+	cache.Status.HitCount++
+	cache.Status.LastHit = z.Pointer(metav1.Now())
+	if err := k8s.Status().Update(ctx, &cache); err != nil {
+		// Hit tracking is best effort, a conflict here should not fail the completion
+		slog.Debug("failed to record cache hit", "cache", cache.Name, "err", err)
 	}
-	return result, true, json.NewDecoder(gz).Decode(&result)
+
+	return result, cache.Usage, true, nil
 }
 
 func toToolCall(call v1.ToolCall) openai.ToolCall {
@@ -149,20 +188,45 @@ func toMessages(ctx context.Context, k8s kclient.Client, namespace string, reque
 	return
 }
 
+type jsonSchema map[string]any
+
+func (j jsonSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any(j))
+}
+
 type CompletionRequest struct {
+	Provider     string
 	Model        string
 	Vision       bool
 	Tools        []v1.Tool
 	Messages     []v1.MessageBody
 	MaxToken     int
 	JSONResponse bool
-	Cache        *bool
+	// ResponseSchema is sent as a JSON schema response format, backends of providers that don't support
+	// schemas fall back to JSON mode
+	ResponseSchema *jsonschema.Schema
+	Cache          *bool
+	// CacheLabels are set on the Cache created for the response
+	CacheLabels map[string]string
+	// Seed is added to the seed derived from the request, so that requests for alternative responses differ
+	Seed int
+}
+
+// Model returns the model the request is sent to, the default model of the client if the request doesn't set one
+func (c *Client) Model(messageRequest CompletionRequest) string {
+	switch {
+	case messageRequest.Model != "":
+		return messageRequest.Model
+	case messageRequest.Vision:
+		return c.defaultVisionModel
+	}
+	return c.defaultModel
 }
 
-func (c *Client) Call(ctx context.Context, k8s kclient.Client, namespace string, messageRequest CompletionRequest, status chan<- v1.MessageBody) (*v1.MessageBody, error) {
+func (c *Client) Call(ctx context.Context, k8s kclient.Client, namespace string, messageRequest CompletionRequest, status chan<- v1.MessageBody) (*v1.MessageBody, v1.Usage, error) {
 	msgs, err := toMessages(ctx, k8s, namespace, messageRequest)
 	if err != nil {
-		return nil, err
+		return nil, v1.Usage{}, err
 	}
 
 	request := openai.ChatCompletionRequest{
@@ -171,19 +235,28 @@ func (c *Client) Call(ctx context.Context, k8s kclient.Client, namespace string,
 		MaxTokens: messageRequest.MaxToken,
 	}
 
-	if messageRequest.JSONResponse {
+	if messageRequest.ResponseSchema != nil {
+		// Backends send the schema as instructions to models that can't enforce it, the response is validated
+		// after the completion either way
+		responseSchema, strict, err := schema.ToJSONSchema(messageRequest.ResponseSchema)
+		if err != nil {
+			return nil, v1.Usage{}, err
+		}
+		request.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "response",
+				Schema: jsonSchema(responseSchema),
+				Strict: strict,
+			},
+		}
+	} else if messageRequest.JSONResponse {
 		request.ResponseFormat = &openai.ChatCompletionResponseFormat{
 			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
 		}
 	}
 
-	if request.Model == "" {
-		if messageRequest.Vision {
-			request.Model = DefaultVisionModel
-		} else {
-			request.Model = DefaultModel
-		}
-	}
+	request.Model = c.Model(messageRequest)
 
 	if request.MaxTokens == 0 {
 		request.MaxTokens = DefaultMaxTokens
@@ -197,7 +270,7 @@ func (c *Client) Call(ctx context.Context, k8s kclient.Client, namespace string,
 			}
 			request.Tools = append(request.Tools, openai.Tool{
 				Type: openai.ToolType(tool.Type),
-				Function: openai.FunctionDefinition{
+				Function: &openai.FunctionDefinition{
 					Name:        tool.Function.Name,
 					Description: tool.Function.Description,
 					Parameters:  params,
@@ -206,14 +279,14 @@ func (c *Client) Call(ctx context.Context, k8s kclient.Client, namespace string,
 		}
 	}
 
-	request.Seed = z.Pointer(hash.Seed(request))
-	response, ok, err := c.fromCache(ctx, k8s, namespace, messageRequest, request)
+	request.Seed = z.Pointer(hash.Seed(request) + messageRequest.Seed)
+	response, usage, ok, err := c.fromCache(ctx, k8s, namespace, messageRequest, request)
 	if err != nil {
-		return nil, err
+		return nil, v1.Usage{}, err
 	} else if !ok {
-		response, err = c.call(ctx, k8s, namespace, request, status)
+		response, usage, err = c.call(ctx, k8s, namespace, messageRequest, request, status)
 		if err != nil {
-			return nil, err
+			return nil, v1.Usage{}, err
 		}
 	}
 
@@ -222,7 +295,27 @@ func (c *Client) Call(ctx context.Context, k8s kclient.Client, namespace string,
 		result = appendMessage(result, response)
 	}
 
-	return &result, nil
+	if usage == nil {
+		// Responses cached before usage was recorded
+		usage = estimateUsage(messageRequest, request, result)
+	}
+	if ok {
+		usage.Cached = true
+	}
+
+	return &result, *usage, nil
+}
+
+// estimateUsage counts the tokens of a request and its result for providers that don't report usage
+func estimateUsage(messageRequest CompletionRequest, request openai.ChatCompletionRequest, result v1.MessageBody) *v1.Usage {
+	usage := &v1.Usage{
+		Model:            request.Model,
+		PromptTokens:     tokens.Messages(request.Model, messageRequest.Messages) + tokens.Tools(request.Model, messageRequest.Tools),
+		CompletionTokens: tokens.Message(request.Model, result),
+		Estimated:        true,
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
 }
 
 func appendMessage(msg v1.MessageBody, response openai.ChatCompletionStreamResponse) v1.MessageBody {
@@ -290,7 +383,7 @@ func override(left, right string) string {
 	return left
 }
 
-func (c *Client) store(ctx context.Context, k8s kclient.Client, key, namespace string, responses []openai.ChatCompletionStreamResponse) error {
+func (c *Client) store(ctx context.Context, k8s kclient.Client, key, namespace string, labels map[string]string, responses []openai.ChatCompletionStreamResponse, usage *v1.Usage) error {
 	buf := &bytes.Buffer{}
 	gz := gzip.NewWriter(buf)
 	err := json.NewEncoder(gz).Encode(responses)
@@ -300,32 +393,40 @@ func (c *Client) store(ctx context.Context, k8s kclient.Client, key, namespace s
 	if err := gz.Close(); err != nil {
 		return err
 	}
-	return k8s.Create(ctx, &v1.Cache{
+	err = k8s.Create(ctx, &v1.Cache{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      key,
 			Namespace: namespace,
+			Labels:    labels,
 		},
 		Content: buf.Bytes(),
+		Usage:   usage,
 	})
+	if apierrors.IsAlreadyExists(err) {
+		// The same request was already stored, such as when caching is disabled for the assistant
+		return nil
+	}
+	return err
 }
 
-func (c *Client) call(ctx context.Context, k8s kclient.Client, namespace string, request openai.ChatCompletionRequest, partial chan<- v1.MessageBody) (responses []openai.ChatCompletionStreamResponse, _ error) {
+func (c *Client) call(ctx context.Context, k8s kclient.Client, namespace string, messageRequest CompletionRequest, request openai.ChatCompletionRequest, partial chan<- v1.MessageBody) (responses []openai.ChatCompletionStreamResponse, _ *v1.Usage, _ error) {
 	cacheKey := c.cacheKey(request)
 	request.Stream = true
 
 	slog.Debug("calling openai", "message", request.Messages)
 	stream, err := c.c.CreateChatCompletionStream(ctx, request)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer stream.Close()
 
 	for {
 		response, err := stream.Recv()
 		if err == io.EOF {
-			return responses, c.store(ctx, k8s, cacheKey, namespace, responses)
+			usage := c.usage(stream, messageRequest, request, responses)
+			return responses, usage, c.store(ctx, k8s, cacheKey, namespace, messageRequest.CacheLabels, responses, usage)
 		} else if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if len(response.Choices) > 0 {
 			slog.Debug("stream", "content", response.Choices[0].Delta.Content)
@@ -339,3 +440,22 @@ func (c *Client) call(ctx context.Context, k8s kclient.Client, namespace string,
 		responses = append(responses, response)
 	}
 }
+
+func (c *Client) usage(stream Stream, messageRequest CompletionRequest, request openai.ChatCompletionRequest, responses []openai.ChatCompletionStreamResponse) *v1.Usage {
+	if usageStream, ok := stream.(UsageStream); ok {
+		if usage, ok := usageStream.Usage(); ok {
+			return &v1.Usage{
+				Model:            request.Model,
+				PromptTokens:     usage.PromptTokens,
+				CompletionTokens: usage.CompletionTokens,
+				TotalTokens:      usage.TotalTokens,
+			}
+		}
+	}
+
+	result := v1.MessageBody{}
+	for _, response := range responses {
+		result = appendMessage(result, response)
+	}
+	return estimateUsage(messageRequest, request, result)
+}
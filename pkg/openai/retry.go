@@ -0,0 +1,139 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// RetryAfterError is an error from a provider that told us how long to wait before trying again
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (r *RetryAfterError) Error() string {
+	return r.Err.Error()
+}
+
+func (r *RetryAfterError) Unwrap() error {
+	return r.Err
+}
+
+// WithRetryAfter wraps err with the delay the provider asked for. err is returned as is if there is no delay.
+func WithRetryAfter(err error, retryAfter time.Duration) error {
+	if err == nil || retryAfter <= 0 {
+		return err
+	}
+	return &RetryAfterError{
+		Err:        err,
+		RetryAfter: retryAfter,
+	}
+}
+
+// RetryAfter returns the delay the provider asked for before retrying, or zero if it didn't say.
+func RetryAfter(err error) time.Duration {
+	var retryErr *RetryAfterError
+	if errors.As(err, &retryErr) {
+		return retryErr.RetryAfter
+	}
+	return 0
+}
+
+// ParseRetryAfter reads the retry-after-ms or Retry-After headers of a response. Retry-After may be
+// either a number of seconds or an HTTP date.
+func ParseRetryAfter(header http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// IsTransient returns true if err is an error that may succeed if the same request is sent again later,
+// such as rate limiting, server errors and dropped connections. Cancellation and the deadline of the caller's
+// context are never transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if RetryAfter(err) > 0 {
+		return true
+	}
+
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.HTTPStatusCode == 0 {
+			// Errors sent in the body of a stream have no status code, so look at the type instead
+			switch apiErr.Type {
+			case "rate_limit_error", "overloaded_error", "api_error", "server_error":
+				return true
+			}
+			return false
+		}
+		return isTransientStatus(apiErr.HTTPStatusCode)
+	}
+
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return isTransientStatus(reqErr.HTTPStatusCode)
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func isTransientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+type retryAfterKey struct{}
+
+// retryAfterTransport records the Retry-After header of responses into the *time.Duration stored in the
+// request context. The go-openai client does not expose response headers on errors.
+type retryAfterTransport struct {
+	next http.RoundTripper
+}
+
+func (r *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := r.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter, ok := req.Context().Value(retryAfterKey{}).(*time.Duration); ok {
+		*retryAfter = ParseRetryAfter(resp.Header)
+	}
+	return resp, nil
+}
@@ -25,8 +25,13 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.AssistantStatus":     schema_pkg_apis_assistantacornio_v1_AssistantStatus(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Cache":               schema_pkg_apis_assistantacornio_v1_Cache(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CacheList":           schema_pkg_apis_assistantacornio_v1_CacheList(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CacheStatus":         schema_pkg_apis_assistantacornio_v1_CacheStatus(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ChatMessageImageURL": schema_pkg_apis_assistantacornio_v1_ChatMessageImageURL(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ContentPart":         schema_pkg_apis_assistantacornio_v1_ContentPart(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Credential":          schema_pkg_apis_assistantacornio_v1_Credential(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CredentialList":      schema_pkg_apis_assistantacornio_v1_CredentialList(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CredentialSpec":      schema_pkg_apis_assistantacornio_v1_CredentialSpec(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CredentialStatus":    schema_pkg_apis_assistantacornio_v1_CredentialStatus(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.FunctionCall":        schema_pkg_apis_assistantacornio_v1_FunctionCall(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.FunctionDefinition":  schema_pkg_apis_assistantacornio_v1_FunctionDefinition(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Image":               schema_pkg_apis_assistantacornio_v1_Image(ref),
@@ -39,17 +44,29 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.InvokeToolStatus":    schema_pkg_apis_assistantacornio_v1_InvokeToolStatus(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Message":             schema_pkg_apis_assistantacornio_v1_Message(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.MessageBody":         schema_pkg_apis_assistantacornio_v1_MessageBody(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.MessageDelta":        schema_pkg_apis_assistantacornio_v1_MessageDelta(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.MessageInput":        schema_pkg_apis_assistantacornio_v1_MessageInput(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.MessageList":         schema_pkg_apis_assistantacornio_v1_MessageList(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.MessageSpec":         schema_pkg_apis_assistantacornio_v1_MessageSpec(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.MessageStatus":       schema_pkg_apis_assistantacornio_v1_MessageStatus(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProvider":       schema_pkg_apis_assistantacornio_v1_ModelProvider(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProviderList":   schema_pkg_apis_assistantacornio_v1_ModelProviderList(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProviderSpec":   schema_pkg_apis_assistantacornio_v1_ModelProviderSpec(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProviderStatus": schema_pkg_apis_assistantacornio_v1_ModelProviderStatus(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.NoOptions":           schema_pkg_apis_assistantacornio_v1_NoOptions(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.SecretKeyRef":        schema_pkg_apis_assistantacornio_v1_SecretKeyRef(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Thread":              schema_pkg_apis_assistantacornio_v1_Thread(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadFork":          schema_pkg_apis_assistantacornio_v1_ThreadFork(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadForkSpec":      schema_pkg_apis_assistantacornio_v1_ThreadForkSpec(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadForkStatus":    schema_pkg_apis_assistantacornio_v1_ThreadForkStatus(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadList":          schema_pkg_apis_assistantacornio_v1_ThreadList(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadSpec":          schema_pkg_apis_assistantacornio_v1_ThreadSpec(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadStatus":        schema_pkg_apis_assistantacornio_v1_ThreadStatus(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Tool":                schema_pkg_apis_assistantacornio_v1_Tool(ref),
 		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ToolCall":            schema_pkg_apis_assistantacornio_v1_ToolCall(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.TruncationPolicy":    schema_pkg_apis_assistantacornio_v1_TruncationPolicy(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Usage":               schema_pkg_apis_assistantacornio_v1_Usage(ref),
+		"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.UsageTotals":         schema_pkg_apis_assistantacornio_v1_UsageTotals(ref),
 		"k8s.io/apimachinery/pkg/api/resource.Quantity":                                            schema_apimachinery_pkg_api_resource_Quantity(ref),
 		"k8s.io/apimachinery/pkg/api/resource.int64Amount":                                         schema_apimachinery_pkg_api_resource_int64Amount(ref),
 		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                            schema_pkg_apis_meta_v1_APIGroup(ref),
@@ -347,6 +364,12 @@ func schema_pkg_apis_assistantacornio_v1_AssistantSpec(ref common.ReferenceCallb
 							Format: "",
 						},
 					},
+					"provider": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
 					"model": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
@@ -389,18 +412,29 @@ func schema_pkg_apis_assistantacornio_v1_AssistantSpec(ref common.ReferenceCallb
 							Format: "",
 						},
 					},
+					"responseSchema": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResponseSchema is the schema responses must match. It implies JSONResponse.",
+							Ref:         ref("github.com/acorn-io/aml/pkg/jsonschema.Schema"),
+						},
+					},
 					"cache": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"boolean"},
 							Format: "",
 						},
 					},
+					"truncation": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.TruncationPolicy"),
+						},
+					},
 				},
 				Required: []string{"parameters"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/acorn-io/aml/pkg/jsonschema.Schema", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Tool"},
+			"github.com/acorn-io/aml/pkg/jsonschema.Schema", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Tool", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.TruncationPolicy"},
 	}
 }
 
@@ -410,6 +444,11 @@ func schema_pkg_apis_assistantacornio_v1_AssistantStatus(ref common.ReferenceCal
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.UsageTotals"),
+						},
+					},
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
@@ -427,7 +466,7 @@ func schema_pkg_apis_assistantacornio_v1_AssistantStatus(ref common.ReferenceCal
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.UsageTotals", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
 	}
 }
 
@@ -463,11 +502,22 @@ func schema_pkg_apis_assistantacornio_v1_Cache(ref common.ReferenceCallback) com
 							Format: "byte",
 						},
 					},
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Usage"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CacheStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CacheStatus", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Usage", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
@@ -519,6 +569,38 @@ func schema_pkg_apis_assistantacornio_v1_CacheList(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_assistantacornio_v1_CacheStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size is the number of bytes of content",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"hitCount": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"lastHit": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_assistantacornio_v1_ChatMessageImageURL(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -585,6 +667,156 @@ func schema_pkg_apis_assistantacornio_v1_ContentPart(ref common.ReferenceCallbac
 	}
 }
 
+func schema_pkg_apis_assistantacornio_v1_Credential(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Credential holds secret values, such as the API keys of model providers, in the runtime store so that tenants of the runtime API can manage their own keys",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CredentialSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CredentialStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CredentialSpec", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.CredentialStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_CredentialList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Credential"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Credential", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_CredentialSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Data are the secret values by key. The values are blank when read from the API, except by the controller, and a blank value in an update keeps the stored value.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_CredentialStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+	}
+}
+
 func schema_pkg_apis_assistantacornio_v1_FunctionCall(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -639,6 +871,13 @@ func schema_pkg_apis_assistantacornio_v1_FunctionDefinition(ref common.Reference
 							Ref: ref("github.com/acorn-io/aml/pkg/jsonschema.Schema"),
 						},
 					},
+					"client": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Client functions are called by the client of the thread instead of the runtime. The client sets spec.output of the InvokeTool to the result of the call.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"name", "parameters"},
 			},
@@ -917,6 +1156,20 @@ func schema_pkg_apis_assistantacornio_v1_InvokeToolSpec(ref common.ReferenceCall
 							Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ToolCall"),
 						},
 					},
+					"cancel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cancel is set when the message making the call is cancelled",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"output": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Output is the result of a call to a client function, set by the client",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -931,6 +1184,12 @@ func schema_pkg_apis_assistantacornio_v1_InvokeToolStatus(ref common.ReferenceCa
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
 					"content": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
@@ -1067,6 +1326,25 @@ func schema_pkg_apis_assistantacornio_v1_MessageBody(ref common.ReferenceCallbac
 	}
 }
 
+func schema_pkg_apis_assistantacornio_v1_MessageDelta(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MessageDelta is content added to a message while it is generated. It is sent by the stream subresource of messages.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"text": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_assistantacornio_v1_MessageInput(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1103,6 +1381,20 @@ func schema_pkg_apis_assistantacornio_v1_MessageInput(ref common.ReferenceCallba
 							Format: "",
 						},
 					},
+					"role": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Role is the role of a message with content. Content with the assistant role is used as a response of the assistant instead of generating one.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"seed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Seed is mixed into the seed of the completion so that alternative completions of the same messages differ",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 			},
 		},
@@ -1193,8 +1485,23 @@ func schema_pkg_apis_assistantacornio_v1_MessageSpec(ref common.ReferenceCallbac
 					},
 					"more": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
-							Format: "",
+							Description: "More means that more messages follow the message, so no response is generated for it and its tool calls are not invoked",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"alternatives": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Alternatives is the number of responses of the assistant to the message in addition to the first one. Each one is generated with a different seed and starts another branch of the thread.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"cancel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cancel stops the generation of the message, and the tools and assistants called by it",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
@@ -1211,6 +1518,12 @@ func schema_pkg_apis_assistantacornio_v1_MessageStatus(ref common.ReferenceCallb
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
 					"message": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
@@ -1228,6 +1541,29 @@ func schema_pkg_apis_assistantacornio_v1_MessageStatus(ref common.ReferenceCallb
 							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"attempts": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"sentMessages": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"droppedMessages": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Usage"),
+						},
+					},
 					"threadName": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
@@ -1236,8 +1572,24 @@ func schema_pkg_apis_assistantacornio_v1_MessageStatus(ref common.ReferenceCallb
 					},
 					"nextMessageName": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "NextMessageName is the child of the message on the active branch of the thread",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"childMessageNames": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ChildMessageNames are all the messages whose parent is this message, in the order they were created. Each one starts a different branch of the thread.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
 					"invokeToolNames": {
@@ -1271,7 +1623,209 @@ func schema_pkg_apis_assistantacornio_v1_MessageStatus(ref common.ReferenceCallb
 			},
 		},
 		Dependencies: []string{
-			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.MessageBody", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.MessageBody", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Usage", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_ModelProvider(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProviderSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProviderStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProviderSpec", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProviderStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_ModelProviderList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProvider"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ModelProvider", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_ModelProviderSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is the API protocol of the provider, defaults to openai",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"baseURL": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"apiKey": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.SecretKeyRef"),
+						},
+					},
+					"defaultModel": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"defaultVisionModel": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"headers": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"modelPrefixes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ModelPrefixes selects this provider for assistants in the namespace whose model starts with one of the prefixes",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"default": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Default selects this provider for assistants in the namespace that don't otherwise match a provider",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.SecretKeyRef"},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_ModelProviderStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
 	}
 }
 
@@ -1301,6 +1855,34 @@ func schema_pkg_apis_assistantacornio_v1_NoOptions(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_assistantacornio_v1_SecretKeyRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SecretKeyRef refers to a key of a Credential in the same namespace",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"name", "key"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_assistantacornio_v1_Thread(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1347,6 +1929,97 @@ func schema_pkg_apis_assistantacornio_v1_Thread(ref common.ReferenceCallback) co
 	}
 }
 
+func schema_pkg_apis_assistantacornio_v1_ThreadFork(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ThreadFork is the request and the result of the fork operation of a thread, which copies the messages of the thread up to a message into a new thread",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadForkSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadForkStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadForkSpec", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.ThreadForkStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_ThreadForkSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"messageName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MessageName is the last message copied. It defaults to the last message of the thread. The results of a tool call are copied with it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"assistantName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AssistantName is the assistant of the new thread. It defaults to the assistant of the thread.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_ThreadForkStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"threadName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_assistantacornio_v1_ThreadList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1419,6 +2092,13 @@ func schema_pkg_apis_assistantacornio_v1_ThreadSpec(ref common.ReferenceCallback
 							Format: "",
 						},
 					},
+					"activeMessageName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActiveMessageName keeps the thread on the branch that contains the message. It is cleared when a message is added, the new message starts the active branch.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -1431,12 +2111,54 @@ func schema_pkg_apis_assistantacornio_v1_ThreadStatus(ref common.ReferenceCallba
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase is the phase of the last message of the thread",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description is a short title of the thread, generated from its first messages",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastMessageName": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
+					"lastMessageTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"messageCount": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"toolCallCount": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"descriptionUsage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DescriptionUsage is the usage of the call that generated the description",
+							Ref:         ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Usage"),
+						},
+					},
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.UsageTotals"),
+						},
+					},
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
@@ -1454,7 +2176,7 @@ func schema_pkg_apis_assistantacornio_v1_ThreadStatus(ref common.ReferenceCallba
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+			"github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.Usage", "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1.UsageTotals", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -1524,6 +2246,160 @@ func schema_pkg_apis_assistantacornio_v1_ToolCall(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_assistantacornio_v1_TruncationPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Strategy defaults to drop-oldest",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastMessages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastMessages is the number of messages kept by the keep-last strategy",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"contextWindow": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ContextWindow overrides the context window size, in tokens, of the model",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_Usage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Usage is the number of tokens used to generate a message",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"model": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"promptTokens": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"completionTokens": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"totalTokens": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"estimated": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Estimated is true if the provider did not report usage and the tokens were counted by the runtime",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"cached": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cached is true if the response was replayed from a Cache, so the provider was not called",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"cachedTokens": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CachedTokens is the number of tokens replayed from a Cache by a message that also called the provider, such as a cached response with a repair call. They are not included in the other token counts.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"cost": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cost is the estimated cost from the price table of the controller. Cached responses have no cost.",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_assistantacornio_v1_UsageTotals(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UsageTotals is the usage of all the messages of a thread or all the threads of an assistant",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"promptTokens": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"completionTokens": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"totalTokens": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"cachedTokens": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CachedTokens is the number of tokens of responses replayed from a Cache. They are not included in the other token counts or the cost.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"completions": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"cachedCompletions": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"cost": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_apimachinery_pkg_api_resource_Quantity(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.EmbedOpenAPIDefinitionIntoV2Extension(common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -0,0 +1,55 @@
+package prices
+
+import (
+	"strings"
+
+	"github.com/acorn-io/aml"
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+)
+
+// Price is the cost of one million tokens
+type Price struct {
+	Prompt     float64 `json:"prompt,omitempty"`
+	Completion float64 `json:"completion,omitempty"`
+}
+
+// Table maps model names, or prefixes of model names, to their price. The longest matching prefix wins.
+type Table map[string]Price
+
+// Load reads a price table from a YAML, JSON or AML file. An empty file name returns an empty table.
+func Load(file string) (Table, error) {
+	result := Table{}
+	if file == "" {
+		return result, nil
+	}
+	return result, aml.UnmarshalFile(file, &result)
+}
+
+func (t Table) lookup(model string) (Price, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+
+	var (
+		match  Price
+		length = -1
+	)
+	for prefix, price := range t {
+		if strings.HasPrefix(model, prefix) && len(prefix) > length {
+			match, length = price, len(prefix)
+		}
+	}
+	return match, length >= 0
+}
+
+// Cost returns the cost of usage, or zero if the model has no price or the usage was replayed from a cache.
+func (t Table) Cost(usage v1.Usage) float64 {
+	if usage.Cached {
+		return 0
+	}
+	price, ok := t.lookup(usage.Model)
+	if !ok {
+		return 0
+	}
+	return (float64(usage.PromptTokens)*price.Prompt + float64(usage.CompletionTokens)*price.Completion) / 1_000_000
+}
@@ -0,0 +1,165 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/acorn-io/assistant-runtime/pkg/anthropic"
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/hash"
+	"github.com/acorn-io/assistant-runtime/pkg/ollama"
+	"github.com/acorn-io/assistant-runtime/pkg/openai"
+	"github.com/acorn-io/baaah/pkg/router"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resolveNamespace looks for a ModelProvider in the namespace that matches the request. The returned
+// provider is nil if no ModelProvider in the namespace matches.
+func (r *Registry) resolveNamespace(ctx context.Context, k8s kclient.Client, namespace, providerName, model string) (Provider, string, error) {
+	var modelProviders v1.ModelProviderList
+	if err := k8s.List(ctx, &modelProviders, &kclient.ListOptions{
+		Namespace: namespace,
+	}); err != nil {
+		return nil, "", err
+	}
+
+	if len(modelProviders.Items) == 0 {
+		return nil, "", nil
+	}
+
+	byName := map[string]*v1.ModelProvider{}
+	for i := range modelProviders.Items {
+		byName[modelProviders.Items[i].Name] = &modelProviders.Items[i]
+	}
+
+	if providerName != "" {
+		if mp, ok := byName[providerName]; ok {
+			return r.fromModelProvider(ctx, k8s, mp, model)
+		}
+		return nil, "", nil
+	}
+
+	if name, rest, ok := strings.Cut(model, "/"); ok {
+		if mp, ok := byName[name]; ok {
+			return r.fromModelProvider(ctx, k8s, mp, rest)
+		}
+	}
+
+	var (
+		match       *v1.ModelProvider
+		matchLength int
+		def         *v1.ModelProvider
+	)
+	for _, name := range sortedNames(byName) {
+		mp := byName[name]
+		for _, prefix := range mp.Spec.ModelPrefixes {
+			if strings.HasPrefix(model, prefix) && len(prefix) > matchLength {
+				match, matchLength = mp, len(prefix)
+			}
+		}
+		if mp.Spec.Default && def == nil {
+			def = mp
+		}
+	}
+
+	if match == nil {
+		match = def
+	}
+	if match == nil {
+		return nil, "", nil
+	}
+	return r.fromModelProvider(ctx, k8s, match, model)
+}
+
+func sortedNames(m map[string]*v1.ModelProvider) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (r *Registry) fromModelProvider(ctx context.Context, k8s kclient.Client, mp *v1.ModelProvider, model string) (Provider, string, error) {
+	var apiKey string
+	if ref := mp.Spec.APIKey; ref != nil {
+		// Keys are Credentials in the runtime store, so tenants of the runtime API can bring their own
+		var credential v1.Credential
+		if err := k8s.Get(ctx, router.Key(mp.Namespace, ref.Name), &credential); err != nil {
+			return nil, "", fmt.Errorf("getting API key for model provider %s: %w", mp.Name, err)
+		}
+		data, ok := credential.Spec.Data[ref.Key]
+		if !ok {
+			return nil, "", fmt.Errorf("key %s not found in credential %s for model provider %s", ref.Key, ref.Name, mp.Name)
+		}
+		apiKey = data
+	}
+
+	// Clients are kept per ModelProvider and replaced when its spec or key changes
+	var (
+		clientKey = router.Key(mp.Namespace, mp.Name).String()
+		configKey = hash.Encode(map[string]any{
+			"spec":   mp.Spec,
+			"apiKey": apiKey,
+		})
+	)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if client, ok := r.clients[clientKey]; ok && client.configKey == configKey {
+		return client.provider, model, nil
+	}
+
+	provider, err := newProvider(mp.Spec, apiKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("model provider %s: %w", mp.Name, err)
+	}
+
+	r.clients[clientKey] = modelProviderClient{
+		configKey: configKey,
+		provider:  provider,
+	}
+	return provider, model, nil
+}
+
+// modelProviderClient is the provider created for a ModelProvider, with the hash of the config it was created from
+type modelProviderClient struct {
+	configKey string
+	provider  Provider
+}
+
+func newProvider(spec v1.ModelProviderSpec, apiKey string) (Provider, error) {
+	switch spec.Type {
+	case "", v1.ModelProviderTypeOpenAI:
+		return openai.NewClientWithBackend(openai.NewBackend(openai.Config{
+			BaseURL: spec.BaseURL,
+			APIKey:  apiKey,
+			Headers: spec.Headers,
+		}), or(spec.DefaultModel, openai.DefaultModel), or(spec.DefaultVisionModel, openai.DefaultVisionModel)), nil
+	case v1.ModelProviderTypeAnthropic:
+		return openai.NewClientWithBackend(anthropic.NewBackend(anthropic.Config{
+			BaseURL: spec.BaseURL,
+			APIKey:  apiKey,
+			Headers: spec.Headers,
+		}), or(spec.DefaultModel, anthropic.DefaultModel), or(spec.DefaultVisionModel, anthropic.DefaultVisionModel)), nil
+	case v1.ModelProviderTypeOllama:
+		if spec.BaseURL == "" {
+			return nil, fmt.Errorf("baseURL is required for ollama")
+		}
+		return openai.NewClientWithBackend(ollama.NewBackend(ollama.Config{
+			BaseURL: spec.BaseURL,
+			Headers: spec.Headers,
+		}), or(spec.DefaultModel, ollama.DefaultModel), or(spec.DefaultVisionModel, ollama.DefaultVisionModel)), nil
+	}
+	return nil, fmt.Errorf("unknown model provider type %s", spec.Type)
+}
+
+func or(left, right string) string {
+	if left != "" {
+		return left
+	}
+	return right
+}
@@ -0,0 +1,161 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/acorn-io/assistant-runtime/pkg/anthropic"
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/ollama"
+	"github.com/acorn-io/assistant-runtime/pkg/openai"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Provider has the same contract as message.CompleteClient, with the provider already chosen
+type Provider interface {
+	Call(ctx context.Context, k8s kclient.Client, namespace string, messageRequest openai.CompletionRequest, status chan<- v1.MessageBody) (*v1.MessageBody, v1.Usage, error)
+	Model(messageRequest openai.CompletionRequest) string
+}
+
+// Registry dispatches completion requests to a provider. ModelProviders in the namespace of the
+// request take precedence over the providers configured for the process. In both cases the provider
+// is chosen by the explicit provider name on the request, then by a "provider/" prefix on the model
+// name, then by model prefixes, and finally falls back to the default provider.
+type Registry struct {
+	defaultProvider string
+	providers       map[string]Provider
+	prefixes        map[string]string
+
+	lock    sync.Mutex
+	clients map[string]modelProviderClient
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: map[string]Provider{},
+		prefixes:  map[string]string{},
+		clients:   map[string]modelProviderClient{},
+	}
+}
+
+// FromEnv returns a Registry with all the providers that are configured in the environment
+func FromEnv(defaultProvider string) (*Registry, error) {
+	r := NewRegistry()
+
+	if openai.Configured() {
+		c, err := openai.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		r.Register(string(v1.ModelProviderTypeOpenAI), c, "gpt-")
+	}
+
+	if anthropic.Configured() {
+		c, err := anthropic.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		r.Register(string(v1.ModelProviderTypeAnthropic), c, "claude-")
+	}
+
+	if ollama.Configured() {
+		c, err := ollama.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		r.Register(string(v1.ModelProviderTypeOllama), c)
+	}
+
+	if defaultProvider != "" {
+		if _, ok := r.providers[defaultProvider]; !ok {
+			return nil, fmt.Errorf("default model provider %s is not configured", defaultProvider)
+		}
+		r.defaultProvider = defaultProvider
+	}
+
+	return r, nil
+}
+
+// Register adds a provider to the registry. The first provider registered is the default.
+func (r *Registry) Register(name string, provider Provider, modelPrefixes ...string) {
+	if r.defaultProvider == "" {
+		r.defaultProvider = name
+	}
+	r.providers[name] = provider
+	for _, prefix := range modelPrefixes {
+		r.prefixes[prefix] = name
+	}
+}
+
+// Lookup returns the provider for the request and the model name to send to that provider
+func (r *Registry) Lookup(ctx context.Context, k8s kclient.Client, namespace, providerName, model string) (Provider, string, error) {
+	provider, resolvedModel, err := r.resolveNamespace(ctx, k8s, namespace, providerName, model)
+	if err != nil {
+		return nil, "", err
+	} else if provider != nil {
+		return provider, resolvedModel, nil
+	}
+
+	if providerName == "" {
+		providerName, model = r.resolve(model)
+	}
+
+	if providerName == "" {
+		// Cached responses are still replayed with no provider, such as caches imported for tests
+		return openai.NewCacheOnlyClient(fmt.Errorf("no model provider is configured in namespace %s or the environment (OPENAI_API_KEY, ANTHROPIC_API_KEY, OLLAMA_URL)", namespace)), model, nil
+	}
+
+	provider, ok := r.providers[providerName]
+	if !ok {
+		return nil, "", fmt.Errorf("model provider %s is not configured", providerName)
+	}
+	return provider, model, nil
+}
+
+func (r *Registry) resolve(model string) (string, string) {
+	if name, rest, ok := strings.Cut(model, "/"); ok {
+		if _, ok := r.providers[name]; ok {
+			return name, rest
+		}
+	}
+
+	prefixes := make([]string, 0, len(r.prefixes))
+	for prefix := range r.prefixes {
+		prefixes = append(prefixes, prefix)
+	}
+	// Longest prefix wins
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(model, prefix) {
+			return r.prefixes[prefix], model
+		}
+	}
+
+	return r.defaultProvider, model
+}
+
+func (r *Registry) Call(ctx context.Context, k8s kclient.Client, namespace string, messageRequest openai.CompletionRequest, status chan<- v1.MessageBody) (*v1.MessageBody, v1.Usage, error) {
+	provider, model, err := r.Lookup(ctx, k8s, namespace, messageRequest.Provider, messageRequest.Model)
+	if err != nil {
+		return nil, v1.Usage{}, err
+	}
+	messageRequest.Model = model
+	return provider.Call(ctx, k8s, namespace, messageRequest, status)
+}
+
+// Model returns the model the request is sent to, including the default model of the provider if the request
+// doesn't set one
+func (r *Registry) Model(ctx context.Context, k8s kclient.Client, namespace string, messageRequest openai.CompletionRequest) (string, error) {
+	provider, model, err := r.Lookup(ctx, k8s, namespace, messageRequest.Provider, messageRequest.Model)
+	if err != nil {
+		return "", err
+	}
+	messageRequest.Model = model
+	return provider.Model(messageRequest), nil
+}
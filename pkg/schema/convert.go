@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/acorn-io/aml/pkg/jsonschema"
+)
+
+// ToJSONSchema converts schema to a standard JSON schema document. The schema type used by assistants
+// serializes descriptions as "Description", definitions as "defs" and items as a list, and omits
+// additionalProperties when it is false, so those are fixed up here. strict is true if the schema meets
+// the requirements of strict structured output, where every property of every object must be required.
+func ToJSONSchema(schema *jsonschema.Schema) (_ map[string]any, strict bool, _ error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false, err
+	}
+
+	strict = true
+	fixup(result, &strict)
+	return result, strict, nil
+}
+
+func fixup(value any, strict *bool) {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			fixup(item, strict)
+		}
+	case map[string]any:
+		if desc, ok := v["Description"]; ok {
+			delete(v, "Description")
+			v["description"] = desc
+		}
+		if defs, ok := v["defs"]; ok {
+			delete(v, "defs")
+			v["$defs"] = defs
+		}
+		if ref, ok := v["$ref"].(string); ok {
+			v["$ref"] = strings.Replace(ref, "#/defs/", "#/$defs/", 1)
+		}
+		if items, ok := v["items"].([]any); ok && len(items) == 1 {
+			v["items"] = items[0]
+		}
+		if props, ok := v["properties"]; ok {
+			if props == nil {
+				v["properties"] = map[string]any{}
+			}
+			if v["type"] == nil {
+				v["type"] = "object"
+			}
+		}
+		if v["type"] == "object" {
+			props, _ := v["properties"].(map[string]any)
+			required, _ := v["required"].([]any)
+			if _, ok := v["additionalProperties"]; !ok && len(props) > 0 {
+				v["additionalProperties"] = false
+			}
+			// Objects with no declared properties can hold anything, which strict mode does not allow
+			if len(props) == 0 || len(props) != len(required) {
+				*strict = false
+			}
+		}
+		for key, item := range v {
+			if key == "properties" || key == "$defs" {
+				// Maps of names to schemas
+				if m, ok := item.(map[string]any); ok {
+					for _, s := range m {
+						fixup(s, strict)
+					}
+				}
+				continue
+			}
+			fixup(item, strict)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/acorn-io/aml/pkg/jsonschema"
+)
+
+// ValidationError lists every place a document does not match a schema
+type ValidationError struct {
+	Errors []string
+}
+
+func (v *ValidationError) Error() string {
+	return strings.Join(v.Errors, "; ")
+}
+
+// Validate checks that data is JSON matching schema. A nil schema accepts any valid JSON. The returned
+// error is a *ValidationError if data is JSON that doesn't match the schema.
+func Validate(schema *jsonschema.Schema, data string) error {
+	var value any
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return &ValidationError{
+			Errors: []string{fmt.Sprintf("invalid JSON: %v", err)},
+		}
+	}
+
+	if schema == nil {
+		return nil
+	}
+
+	v := validator{
+		root: schema,
+	}
+	v.schema("$", *schema, value)
+	if len(v.errors) > 0 {
+		return &ValidationError{
+			Errors: v.errors,
+		}
+	}
+	return nil
+}
+
+type validator struct {
+	root   *jsonschema.Schema
+	errors []string
+}
+
+func (v *validator) errorf(path, format string, args ...any) {
+	v.errors = append(v.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) schema(path string, schema jsonschema.Schema, value any) {
+	if !v.property(path, schema.Property, value) {
+		return
+	}
+
+	if len(schema.Properties) == 0 && len(schema.Required) == 0 {
+		return
+	}
+
+	obj, ok := value.(map[string]any)
+	if !ok {
+		if schema.Type == "" {
+			v.errorf(path, "expected object, got %s", typeOf(value))
+		}
+		return
+	}
+
+	for _, key := range schema.Required {
+		if _, ok := obj[key]; !ok {
+			v.errorf(path, "missing required property %q", key)
+		}
+	}
+
+	keys := make([]string, 0, len(schema.Properties))
+	for key := range schema.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if fieldValue, ok := obj[key]; ok {
+			v.property(path+"."+key, schema.Properties[key], fieldValue)
+		}
+	}
+}
+
+// property validates the type, reference and items of a property. It returns false if value is of the wrong
+// type, so that callers don't report more errors about its contents.
+func (v *validator) property(path string, prop jsonschema.Property, value any) bool {
+	if prop.Ref != "" {
+		def, ok := v.ref(prop.Ref)
+		if !ok {
+			v.errorf(path, "unknown reference %s", prop.Ref)
+			return false
+		}
+		v.schema(path, def, value)
+		return true
+	}
+
+	if prop.Type != "" && !isType(prop.Type, value) {
+		v.errorf(path, "expected %s, got %s", prop.Type, typeOf(value))
+		return false
+	}
+
+	if items, ok := value.([]any); ok && len(prop.Items) > 0 {
+		for i, item := range items {
+			v.schema(fmt.Sprintf("%s[%d]", path, i), prop.Items[0], item)
+		}
+	}
+
+	return true
+}
+
+func (v *validator) ref(ref string) (jsonschema.Schema, bool) {
+	if ref == "#" {
+		return *v.root, true
+	}
+	for _, prefix := range []string{"#/defs/", "#/$defs/", "#/definitions/"} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			def, ok := v.root.Defs[name]
+			return def, ok
+		}
+	}
+	return jsonschema.Schema{}, false
+}
+
+func isType(t string, value any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	// Unknown types are not validated
+	return true
+}
+
+func typeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", value)
+}
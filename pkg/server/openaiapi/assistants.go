@@ -0,0 +1,224 @@
+package openaiapi
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/acorn-io/aml/pkg/jsonschema"
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/sashabaranov/go-openai"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// assistantRequest creates or modifies an assistant. Fields that are not set are not modified.
+type assistantRequest struct {
+	Model        *string                 `json:"model,omitempty"`
+	Name         *string                 `json:"name,omitempty"`
+	Description  *string                 `json:"description,omitempty"`
+	Instructions *string                 `json:"instructions,omitempty"`
+	Tools        *[]openai.AssistantTool `json:"tools,omitempty"`
+	Metadata     map[string]any          `json:"metadata,omitempty"`
+}
+
+func (h *Handler) assistants(r *request) error {
+	switch r.Method {
+	case http.MethodGet:
+		var assistants v1.AssistantList
+		if err := h.client.List(r.Context(), &assistants, kclient.InNamespace(h.namespace)); err != nil {
+			return err
+		}
+		sortByCreation(assistants.Items)
+
+		page, hasMore, err := paginate(r, assistants.Items, func(a v1.Assistant) string { return a.Name })
+		if err != nil {
+			return err
+		}
+
+		result := make([]openai.Assistant, 0, len(page))
+		for i := range page {
+			result = append(result, toAssistant(&page[i]))
+		}
+		return writeList(r, result, hasMore, func(a openai.Assistant) string { return a.ID })
+	case http.MethodPost:
+		var body assistantRequest
+		if err := r.decode(&body); err != nil {
+			return err
+		}
+		if body.Model == nil {
+			return badRequest("model is required")
+		}
+
+		assistant := &v1.Assistant{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "asst-",
+				Namespace:    h.namespace,
+			},
+		}
+		if err := body.apply(assistant); err != nil {
+			return err
+		}
+		if err := h.client.Create(r.Context(), assistant); err != nil {
+			return err
+		}
+		return r.write(toAssistant(assistant))
+	}
+	return errMethodNotAllowed
+}
+
+func (h *Handler) assistant(r *request, id string) error {
+	var assistant v1.Assistant
+	if err := h.client.Get(r.Context(), router.Key(h.namespace, id), &assistant); err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return r.write(toAssistant(&assistant))
+	case http.MethodPost:
+		var body assistantRequest
+		if err := r.decode(&body); err != nil {
+			return err
+		}
+		if err := body.apply(&assistant); err != nil {
+			return err
+		}
+		if err := h.client.Update(r.Context(), &assistant); err != nil {
+			return err
+		}
+		return r.write(toAssistant(&assistant))
+	case http.MethodDelete:
+		if err := h.client.Delete(r.Context(), &assistant); err != nil {
+			return err
+		}
+		return r.write(openai.AssistantDeleteResponse{
+			ID:      assistant.Name,
+			Object:  "assistant.deleted",
+			Deleted: true,
+		})
+	}
+	return errMethodNotAllowed
+}
+
+func (in assistantRequest) apply(assistant *v1.Assistant) error {
+	if in.Model != nil {
+		assistant.Spec.Model = *in.Model
+	}
+	if in.Name != nil {
+		assistant.Spec.Name = *in.Name
+	}
+	if in.Description != nil {
+		assistant.Spec.Description = *in.Description
+	}
+	if in.Instructions != nil {
+		assistant.Spec.Instructions = *in.Instructions
+	}
+	if in.Tools != nil {
+		tools, err := fromTools(*in.Tools)
+		if err != nil {
+			return err
+		}
+		assistant.Spec.Tools = tools
+	}
+	return setMetadata(&assistant.ObjectMeta, in.Metadata)
+}
+
+// fromTools converts the tools of an assistant request. The functions of the OpenAI API are called by the
+// client, so they become client functions.
+func fromTools(tools []openai.AssistantTool) (result []v1.Tool, _ error) {
+	for _, tool := range tools {
+		if tool.Type != openai.AssistantToolTypeFunction || tool.Function == nil {
+			return nil, badRequest("tools of type %s are not supported", tool.Type)
+		}
+
+		var params *jsonschema.Schema
+		if tool.Function.Parameters != nil {
+			data, err := json.Marshal(tool.Function.Parameters)
+			if err != nil {
+				return nil, badRequest("invalid parameters of function %s: %v", tool.Function.Name, err)
+			}
+			params = &jsonschema.Schema{}
+			if err := json.Unmarshal(data, params); err != nil {
+				return nil, badRequest("invalid parameters of function %s: %v", tool.Function.Name, err)
+			}
+		}
+
+		result = append(result, v1.Tool{
+			Type: v1.ToolTypeFunction,
+			Function: v1.FunctionDefinition{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  params,
+				Client:      true,
+			},
+		})
+	}
+	return result, nil
+}
+
+func toTools(tools []v1.Tool) []openai.AssistantTool {
+	result := []openai.AssistantTool{}
+	for _, tool := range tools {
+		result = append(result, openai.AssistantTool{
+			Type: openai.AssistantToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  tool.Function.Parameters,
+			},
+		})
+	}
+	return result
+}
+
+func toAssistant(assistant *v1.Assistant) openai.Assistant {
+	result := openai.Assistant{
+		ID:        assistant.Name,
+		Object:    "assistant",
+		CreatedAt: assistant.CreationTimestamp.Unix(),
+		Model:     assistant.Spec.Model,
+		Tools:     toTools(assistant.Spec.Tools),
+		Metadata:  metadata(assistant.ObjectMeta),
+	}
+	if assistant.Spec.Name != "" {
+		result.Name = &assistant.Spec.Name
+	}
+	if assistant.Spec.Description != "" {
+		result.Description = &assistant.Spec.Description
+	}
+	if assistant.Spec.Instructions != "" {
+		result.Instructions = &assistant.Spec.Instructions
+	}
+	return result
+}
+
+func metadata(obj metav1.ObjectMeta) map[string]any {
+	return *v1.MetadataFromAnnotation(obj.Annotations)
+}
+
+func setMetadata(obj *metav1.ObjectMeta, metadata map[string]any) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	return v1.AddMetadataToAnnotations(obj.Annotations, &metadata)
+}
+
+// sortByCreation sorts objects from the oldest to the newest
+func sortByCreation[T any, PT interface {
+	*T
+	kclient.Object
+}](objs []T) {
+	slices.SortStableFunc(objs, func(a, b T) int {
+		ta, tb := PT(&a).GetCreationTimestamp(), PT(&b).GetCreationTimestamp()
+		if c := ta.Time.Compare(tb.Time); c != 0 {
+			return c
+		}
+		return cmp.Compare(PT(&a).GetName(), PT(&b).GetName())
+	})
+}
@@ -0,0 +1,392 @@
+package openaiapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/sashabaranov/go-openai"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const runPollInterval = 250 * time.Millisecond
+
+// chatCompletions answers a chat completion with the assistant named by the model of the request. The messages
+// are added to a new thread of the assistant, so the instructions and tools of the assistant are used, and the
+// tool calls are made by the runtime. Only the final answer of the assistant is returned.
+//
+// The sampling parameters of the request are ignored, they are part of the assistant. The thread is deleted once
+// the response is written, the controller deletes its messages.
+func (h *Handler) chatCompletions(r *request) error {
+	if r.Method != http.MethodPost {
+		return errMethodNotAllowed
+	}
+
+	var body openai.ChatCompletionRequest
+	if err := r.decode(&body); err != nil {
+		return err
+	}
+
+	switch {
+	case body.Model == "":
+		return badRequest("model is required")
+	case len(body.Tools) > 0 || len(body.Functions) > 0:
+		return badRequest("tools can't be passed, the tools of assistant %s are used", body.Model)
+	case body.N > 1:
+		return badRequest("only one choice can be generated")
+	case len(body.Messages) == 0:
+		return badRequest("messages are required")
+	}
+
+	var assistant v1.Assistant
+	if err := h.client.Get(r.Context(), router.Key(h.namespace, body.Model), &assistant); apierrors.IsNotFound(err) {
+		return &apiError{
+			status:  http.StatusNotFound,
+			kind:    "invalid_request_error",
+			message: fmt.Sprintf("the model %s does not exist, models are the names of assistants", body.Model),
+		}
+	} else if err != nil {
+		return err
+	}
+
+	var inputs []v1.MessageInput
+	for _, msg := range body.Messages {
+		input, ok, err := chatInput(msg)
+		if err != nil {
+			return err
+		} else if ok {
+			inputs = append(inputs, input)
+		}
+	}
+	if len(inputs) == 0 || inputs[len(inputs)-1].Role == v1.RoleTypeAssistant {
+		return badRequest("the last message must be a user message")
+	}
+
+	thread := &v1.Thread{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "chat-",
+			Namespace:    h.namespace,
+		},
+		Spec: v1.ThreadSpec{
+			AssistantName: assistant.Name,
+		},
+	}
+	if err := h.client.Create(r.Context(), thread); err != nil {
+		return err
+	}
+	defer func() {
+		// The context of the request may be done
+		_ = kclient.IgnoreNotFound(h.client.Delete(context.Background(), thread))
+	}()
+
+	for _, input := range inputs {
+		if _, err := h.addMessage(r.Context(), thread, input, nil); err != nil {
+			return err
+		}
+	}
+
+	id, err := h.startRun(r.Context(), thread, &assistant)
+	if err != nil {
+		return err
+	}
+
+	if body.Stream {
+		return h.streamChat(r, thread, id, body.StreamOptions != nil && body.StreamOptions.IncludeUsage)
+	}
+
+	run, msgs, err := h.waitForRun(r.Context(), thread, id)
+	if err != nil {
+		return err
+	}
+
+	return r.write(openai.ChatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: run.CreatedAt,
+		Model:   assistant.Name,
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Message: openai.ChatCompletionMessage{
+					Role:    openai.ChatMessageRoleAssistant,
+					Content: textOf(&msgs[len(msgs)-1]),
+				},
+				FinishReason: openai.FinishReasonStop,
+			},
+		},
+		Usage: run.Usage,
+	})
+}
+
+// chatInput converts a message of a chat completion. System messages are dropped, the instructions of the
+// assistant replace them.
+func chatInput(msg openai.ChatCompletionMessage) (result v1.MessageInput, _ bool, _ error) {
+	switch msg.Role {
+	case openai.ChatMessageRoleSystem:
+		return result, false, nil
+	case openai.ChatMessageRoleUser:
+	case openai.ChatMessageRoleAssistant:
+		if len(msg.ToolCalls) > 0 || msg.FunctionCall != nil {
+			return result, false, badRequest("messages with tool calls can't be passed, the tools are called by the runtime")
+		}
+		result.Role = v1.RoleTypeAssistant
+	default:
+		return result, false, badRequest("messages with the role %s can't be passed", msg.Role)
+	}
+
+	if msg.Content != "" {
+		result.Content = v1.Text(msg.Content)
+	}
+	for _, part := range msg.MultiContent {
+		switch {
+		case part.Type == openai.ChatMessagePartTypeText:
+			result.Content = append(result.Content, v1.ContentPart{Text: part.Text})
+		case part.Type == openai.ChatMessagePartTypeImageURL && part.ImageURL != nil:
+			result.Content = append(result.Content, v1.ContentPart{
+				Image: &v1.ChatMessageImageURL{
+					URL:    part.ImageURL.URL,
+					Detail: v1.ImageURLDetail(part.ImageURL.Detail),
+				},
+			})
+		default:
+			return result, false, badRequest("content parts of type %s are not supported", part.Type)
+		}
+	}
+	if len(result.Content) == 0 {
+		return result, false, badRequest("content is required")
+	}
+
+	return result, true, nil
+}
+
+// waitForRun waits for the run to complete, and returns it with its messages. The run is cancelled if it can't
+// complete.
+func (h *Handler) waitForRun(ctx context.Context, thread *v1.Thread, id string) (run openai.Run, msgs []v1.Message, err error) {
+	err = wait.PollUntilContextCancel(ctx, runPollInterval, true, func(ctx context.Context) (bool, error) {
+		msgs, err = h.getRun(ctx, thread, id)
+		if err != nil {
+			return false, err
+		}
+		run, err = h.toRun(ctx, thread, msgs)
+		if err != nil {
+			return false, err
+		}
+		return runDone(run)
+	})
+	if err != nil && len(msgs) > 0 {
+		// Don't leave the run behind when the client goes away or can't handle it, with a new context since the
+		// one of the request may be done
+		_ = h.cancel(context.Background(), msgs)
+	}
+	return run, msgs, err
+}
+
+// runDone is true once the run completed, or an error if it can't complete
+func runDone(run openai.Run) (bool, error) {
+	switch run.Status {
+	case openai.RunStatusCompleted:
+		return true, nil
+	case openai.RunStatusFailed:
+		return false, &apiError{
+			status:  http.StatusInternalServerError,
+			kind:    "server_error",
+			message: run.LastError.Message,
+		}
+	case openai.RunStatusCancelled:
+		return false, &apiError{
+			status:  http.StatusInternalServerError,
+			kind:    "server_error",
+			message: "the response was cancelled",
+		}
+	case openai.RunStatusRequiresAction:
+		return false, badRequest("assistant %s calls functions of the client, which chat completions can't return",
+			run.AssistantID)
+	}
+	return false, nil
+}
+
+// streamChat sends the content of the run as chunks of a chat completion while it is generated. Content is
+// streamed from each completion of the run, completions that call tools normally don't have any.
+func (h *Handler) streamChat(r *request, thread *v1.Thread, id string, includeUsage bool) error {
+	var (
+		ctx      = r.Context()
+		streamed = map[string]string{}
+		run      openai.Run
+		msgs     []v1.Message
+		chunk    = openai.ChatCompletionStreamResponse{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: time.Now().Unix(),
+			Model:   thread.Spec.AssistantName,
+		}
+		send = func(delta openai.ChatCompletionStreamChoiceDelta, finishReason openai.FinishReason) error {
+			chunk.Choices = []openai.ChatCompletionStreamChoice{
+				{
+					Delta:        delta,
+					FinishReason: finishReason,
+				},
+			}
+			return writeEvent(r.rw, chunk)
+		}
+	)
+
+	r.rw.Header().Set("Content-Type", "text/event-stream")
+	r.rw.Header().Set("Cache-Control", "no-cache")
+	r.rw.WriteHeader(http.StatusOK)
+
+	if err := send(openai.ChatCompletionStreamChoiceDelta{Role: openai.ChatMessageRoleAssistant}, ""); err != nil {
+		return nil
+	}
+
+	err := wait.PollUntilContextCancel(ctx, runPollInterval, true, func(ctx context.Context) (bool, error) {
+		var err error
+		msgs, err = h.getRun(ctx, thread, id)
+		if err != nil {
+			return false, err
+		}
+		run, err = h.toRun(ctx, thread, msgs)
+		if err != nil {
+			return false, err
+		}
+
+		last := msgs[len(msgs)-1]
+		if _, ok := streamed[last.Name]; !ok && last.Spec.Input.Completion && !last.Status.Phase.Done() {
+			if err := h.streamMessage(ctx, &last, func(text string) error {
+				streamed[last.Name] += text
+				return send(openai.ChatCompletionStreamChoiceDelta{Content: text}, "")
+			}); err != nil {
+				return false, err
+			}
+		}
+
+		return runDone(run)
+	})
+	if err != nil {
+		if len(msgs) > 0 {
+			_ = h.cancel(context.Background(), msgs)
+		}
+		if ctx.Err() == nil {
+			writeStreamError(r.rw, err)
+		}
+		return nil
+	}
+
+	// Send what was generated before the stream was opened
+	last := &msgs[len(msgs)-1]
+	if rest, ok := strings.CutPrefix(textOf(last), streamed[last.Name]); ok && rest != "" {
+		if err := send(openai.ChatCompletionStreamChoiceDelta{Content: rest}, ""); err != nil {
+			return nil
+		}
+	}
+
+	if err := send(openai.ChatCompletionStreamChoiceDelta{}, openai.FinishReasonStop); err != nil {
+		return nil
+	}
+
+	if includeUsage {
+		chunk.Choices = []openai.ChatCompletionStreamChoice{}
+		chunk.Usage = &run.Usage
+		if err := writeEvent(r.rw, chunk); err != nil {
+			return nil
+		}
+	}
+
+	_, _ = fmt.Fprint(r.rw, "data: [DONE]\n\n")
+	return nil
+}
+
+// streamMessage calls send with the text added to msg until it is generated, from the stream subresource of
+// the message
+func (h *Handler) streamMessage(ctx context.Context, msg *v1.Message, send func(text string) error) error {
+	url := fmt.Sprintf("%s/apis/%s/namespaces/%s/messages/%s/stream", strings.TrimSuffix(h.restConfig.Host, "/"),
+		v1.SchemeGroupVersion, msg.Namespace, msg.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to stream message %s: %s", msg.Name, resp.Status)
+	}
+
+	var (
+		event   string
+		scanner = bufio.NewScanner(resp.Body)
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && event == "delta":
+			var delta v1.MessageDelta
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &delta); err != nil {
+				return err
+			}
+			if delta.Text == "" {
+				continue
+			}
+			if err := send(delta.Text); err != nil {
+				return err
+			}
+		case event == "done":
+			return nil
+		}
+	}
+
+	// Content sent before a "reset" can't be taken back from the client. The stream ends early if this falls
+	// behind, the rest of the final answer is sent once the run completes.
+	return scanner.Err()
+}
+
+func writeEvent(rw http.ResponseWriter, data any) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(rw, "data: %s\n\n", bytes); err != nil {
+		return err
+	}
+	if flusher, ok := rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// writeStreamError sends an error after the stream started, when the status can't be changed anymore
+func writeStreamError(rw http.ResponseWriter, err error) {
+	e, ok := err.(*apiError)
+	if !ok {
+		e = &apiError{kind: "server_error", message: err.Error()}
+	}
+	_ = writeEvent(rw, map[string]any{
+		"error": map[string]any{
+			"message": e.message,
+			"type":    e.kind,
+			"param":   nil,
+			"code":    nil,
+		},
+	})
+}
+
+func textOf(msg *v1.Message) string {
+	var texts []string
+	for _, content := range msg.Status.Message.Content {
+		texts = append(texts, content.Text)
+	}
+	return strings.Join(texts, "")
+}
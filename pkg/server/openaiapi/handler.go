@@ -0,0 +1,194 @@
+// Package openaiapi serves the OpenAI Assistants API from the assistants, threads and messages of the runtime,
+// so that clients written for OpenAI can use the runtime by changing their base URL. Chat completions are
+// answered by the assistant named by the model.
+package openaiapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/client-go/rest"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Prefix is the path the API is served under. The SDKs append paths such as /assistants to it.
+const Prefix = "/openai/v1"
+
+// ChatCompletionsPath is where chat completions are also served, so that clients of chat completions can use the
+// runtime as their base URL like with OpenAI.
+const ChatCompletionsPath = "/v1/chat/completions"
+
+const waitTimeout = 30 * time.Second
+
+type Handler struct {
+	client     kclient.Client
+	restConfig *rest.Config
+	httpClient *http.Client
+	namespace  string
+	authn      authenticator.Request
+}
+
+func NewHandler(client kclient.Client, restConfig *rest.Config, namespace string, authn authenticator.Request) (*Handler, error) {
+	// The HTTP client streams messages from the API server
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{
+		client:     client,
+		restConfig: restConfig,
+		httpClient: httpClient,
+		namespace:  namespace,
+		authn:      authn,
+	}, nil
+}
+
+// request is an API request, with the path split after the prefix
+type request struct {
+	*http.Request
+	rw   http.ResponseWriter
+	path []string
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if h.authn != nil {
+		if _, ok, err := h.authn.AuthenticateRequest(req); err != nil || !ok {
+			writeError(rw, http.StatusUnauthorized, "invalid_request_error", "invalid or missing API key")
+			return
+		}
+	}
+
+	r := &request{
+		Request: req,
+		rw:      rw,
+		path:    strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, Prefix), "/"), "/"),
+	}
+	if req.URL.Path == ChatCompletionsPath {
+		r.path = []string{"chat", "completions"}
+	}
+
+	var err error
+	switch {
+	case r.match("assistants"):
+		err = h.assistants(r)
+	case r.match("assistants", "*"):
+		err = h.assistant(r, r.path[1])
+	case r.match("chat", "completions"):
+		err = h.chatCompletions(r)
+	case r.match("threads"):
+		err = h.createThread(r)
+	case r.match("threads", "*"):
+		err = h.thread(r, r.path[1])
+	case r.match("threads", "*", "messages"):
+		err = h.messages(r, r.path[1])
+	case r.match("threads", "*", "messages", "*"):
+		err = h.message(r, r.path[1], r.path[3])
+	case r.match("threads", "*", "runs"):
+		err = h.runs(r, r.path[1])
+	case r.match("threads", "*", "runs", "*"):
+		err = h.run(r, r.path[1], r.path[3], "")
+	case r.match("threads", "*", "runs", "*", "*"):
+		err = h.run(r, r.path[1], r.path[3], r.path[4])
+	default:
+		err = errNotFound
+	}
+
+	if err != nil {
+		writeAPIError(rw, err)
+	}
+}
+
+// match checks the path of the request against segments, where * matches any segment
+func (r *request) match(segments ...string) bool {
+	if len(r.path) != len(segments) {
+		return false
+	}
+	for i, segment := range segments {
+		if segment != "*" && segment != r.path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *request) decode(obj any) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func (r *request) write(obj any) error {
+	r.rw.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(r.rw).Encode(obj)
+}
+
+// apiError is an error returned in the format of the OpenAI API
+type apiError struct {
+	status  int
+	kind    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+var (
+	errNotFound         = &apiError{status: http.StatusNotFound, kind: "invalid_request_error", message: "not found"}
+	errMethodNotAllowed = &apiError{status: http.StatusMethodNotAllowed, kind: "invalid_request_error", message: "method not allowed"}
+)
+
+func badRequest(format string, args ...any) error {
+	return &apiError{
+		status:  http.StatusBadRequest,
+		kind:    "invalid_request_error",
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+func writeAPIError(rw http.ResponseWriter, err error) {
+	var e *apiError
+	switch {
+	case errors.As(err, &e):
+		writeError(rw, e.status, e.kind, e.message)
+	case apierrors.IsNotFound(err):
+		writeError(rw, http.StatusNotFound, "invalid_request_error", err.Error())
+	case apierrors.IsBadRequest(err), apierrors.IsInvalid(err), apierrors.IsAlreadyExists(err):
+		writeError(rw, http.StatusBadRequest, "invalid_request_error", err.Error())
+	case apierrors.IsConflict(err):
+		writeError(rw, http.StatusConflict, "invalid_request_error", err.Error())
+	default:
+		writeError(rw, http.StatusInternalServerError, "server_error", err.Error())
+	}
+}
+
+func writeError(rw http.ResponseWriter, status int, kind, message string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(map[string]any{
+		"error": map[string]any{
+			"message": message,
+			"type":    kind,
+			"param":   nil,
+			"code":    nil,
+		},
+	})
+}
+
+// waitFor polls until condition is true. The objects created by the API are linked by the controller, and the
+// next request may depend on that.
+func waitFor(ctx context.Context, condition func(ctx context.Context) (bool, error)) error {
+	return wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, waitTimeout, true, condition)
+}
@@ -0,0 +1,75 @@
+package openaiapi
+
+import (
+	"slices"
+	"strconv"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type listResponse[T any] struct {
+	Object  string  `json:"object"`
+	Data    []T     `json:"data"`
+	FirstID *string `json:"first_id"`
+	LastID  *string `json:"last_id"`
+	HasMore bool    `json:"has_more"`
+}
+
+// paginate returns the page of items, which are ordered from the oldest to the newest, selected by the limit,
+// order, after and before parameters of the request, and whether more items follow the page
+func paginate[T any](r *request, items []T, id func(T) string) ([]T, bool, error) {
+	query := r.URL.Query()
+
+	limit := defaultLimit
+	if s := query.Get("limit"); s != "" {
+		var err error
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return nil, false, badRequest("limit must be a number from 1 to %d", maxLimit)
+		}
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+		items = slices.Clone(items)
+		slices.Reverse(items)
+	case "asc":
+	default:
+		return nil, false, badRequest("order must be asc or desc")
+	}
+
+	if after := query.Get("after"); after != "" {
+		i := slices.IndexFunc(items, func(item T) bool { return id(item) == after })
+		if i < 0 {
+			// A cursor that isn't in the list, such as a deleted item, would otherwise restart the listing
+			return nil, false, badRequest("after %s is not in the list", after)
+		}
+		items = items[i+1:]
+	}
+	if before := query.Get("before"); before != "" {
+		if i := slices.IndexFunc(items, func(item T) bool { return id(item) == before }); i >= 0 {
+			items = items[:i]
+		}
+	}
+
+	if len(items) > limit {
+		return items[:limit], true, nil
+	}
+	return items, false, nil
+}
+
+func writeList[T any](r *request, data []T, hasMore bool, id func(T) string) error {
+	result := listResponse[T]{
+		Object:  "list",
+		Data:    data,
+		HasMore: hasMore,
+	}
+	if len(data) > 0 {
+		first, last := id(data[0]), id(data[len(data)-1])
+		result.FirstID, result.LastID = &first, &last
+	}
+	return r.write(result)
+}
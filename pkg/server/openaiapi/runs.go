@@ -0,0 +1,402 @@
+package openaiapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/z"
+	"github.com/sashabaranov/go-openai"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A run is the response of the assistant to the user messages before it, made of the completions, tool calls
+// and tool results that follow them. The ID of a run is the name of its first completion.
+
+type toolOutputsRequest struct {
+	ToolOutputs []struct {
+		ToolCallID string `json:"tool_call_id"`
+		// Output is usually a string, anything else is sent as JSON
+		Output json.RawMessage `json:"output"`
+	} `json:"tool_outputs"`
+}
+
+func (h *Handler) runs(r *request, threadID string) error {
+	thread, err := h.getThread(r.Context(), threadID)
+	if err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		msgs, err := h.activeMessages(r.Context(), thread)
+		if err != nil {
+			return err
+		}
+
+		result := []openai.Run{}
+		for _, run := range splitRuns(msgs) {
+			converted, err := h.toRun(r.Context(), thread, run)
+			if err != nil {
+				return err
+			}
+			result = append(result, converted)
+		}
+
+		page, hasMore, err := paginate(r, result, func(run openai.Run) string { return run.ID })
+		if err != nil {
+			return err
+		}
+		return writeList(r, page, hasMore, func(run openai.Run) string { return run.ID })
+	case http.MethodPost:
+		return h.createRun(r, thread)
+	}
+	return errMethodNotAllowed
+}
+
+func (h *Handler) run(r *request, threadID, id, action string) error {
+	thread, err := h.getThread(r.Context(), threadID)
+	if err != nil {
+		return err
+	}
+
+	run, err := h.getRun(r.Context(), thread, id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+	case action == "submit_tool_outputs" && r.Method == http.MethodPost:
+		if err := h.submitToolOutputs(r, thread, run); err != nil {
+			return err
+		}
+	case action == "cancel" && r.Method == http.MethodPost:
+		if err := h.cancel(r.Context(), run); err != nil {
+			return err
+		}
+	case action == "" || action == "submit_tool_outputs" || action == "cancel":
+		return errMethodNotAllowed
+	default:
+		return errNotFound
+	}
+
+	return h.writeRun(r, thread, id)
+}
+
+func (h *Handler) writeRun(r *request, thread *v1.Thread, id string) error {
+	run, err := h.getRun(r.Context(), thread, id)
+	if err != nil {
+		return err
+	}
+	result, err := h.toRun(r.Context(), thread, run)
+	if err != nil {
+		return err
+	}
+	return r.write(result)
+}
+
+func (h *Handler) getRun(ctx context.Context, thread *v1.Thread, id string) ([]v1.Message, error) {
+	msgs, err := h.activeMessages(ctx, thread)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range splitRuns(msgs) {
+		if run[0].Name == id {
+			return run, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+// createRun starts the response of the assistant to the messages added since the last run
+func (h *Handler) createRun(r *request, thread *v1.Thread) error {
+	var body openai.RunRequest
+	if err := r.decode(&body); err != nil {
+		return err
+	}
+
+	switch {
+	case body.AssistantID == "":
+		return badRequest("assistant_id is required")
+	case body.Model != "" || body.Instructions != "" || body.AdditionalInstructions != "" || len(body.Tools) > 0:
+		return badRequest("model, instructions and tools of the assistant can't be overridden by a run")
+	}
+
+	var assistant v1.Assistant
+	if err := h.client.Get(r.Context(), router.Key(h.namespace, body.AssistantID), &assistant); apierrors.IsNotFound(err) {
+		return badRequest("assistant %s not found", body.AssistantID)
+	} else if err != nil {
+		return err
+	}
+
+	id, err := h.startRun(r.Context(), thread, &assistant)
+	if err != nil {
+		return err
+	}
+
+	return h.writeRun(r, thread, id)
+}
+
+// startRun starts the response of assistant to the messages added to thread since the last run, and returns
+// the ID of the run
+func (h *Handler) startRun(ctx context.Context, thread *v1.Thread, assistant *v1.Assistant) (string, error) {
+	msgs, err := h.activeMessages(ctx, thread)
+	if err != nil {
+		return "", err
+	}
+	if len(msgs) == 0 {
+		return "", badRequest("thread %s has no messages", thread.Name)
+	}
+
+	last := msgs[len(msgs)-1]
+	if runActive(&last) {
+		return "", badRequest("thread %s already has an active run", thread.Name)
+	} else if last.Status.Message.Role != v1.RoleTypeUser {
+		return "", badRequest("thread %s has no messages since the last run", thread.Name)
+	}
+
+	if thread.Spec.AssistantName != assistant.Name {
+		thread.Spec.AssistantName = assistant.Name
+		if err := h.client.Update(ctx, thread); err != nil {
+			return "", err
+		}
+	}
+
+	// Setting spec.more to false makes the controller generate the response to the last message
+	patch := kclient.MergeFrom(last.DeepCopy())
+	last.Spec.More = false
+	if err := h.client.Patch(ctx, &last, patch); err != nil {
+		return "", err
+	}
+
+	id := last.ResponseName(0)
+	if err := waitFor(ctx, func(ctx context.Context) (bool, error) {
+		var response v1.Message
+		if err := h.client.Get(ctx, router.Key(thread.Namespace, id), &response); apierrors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return response.Status.ThreadName == thread.Name, nil
+	}); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// submitToolOutputs sets the output of the calls of client functions made by the last message of the run
+func (h *Handler) submitToolOutputs(r *request, thread *v1.Thread, run []v1.Message) error {
+	var body toolOutputsRequest
+	if err := r.decode(&body); err != nil {
+		return err
+	}
+
+	invokes, err := h.pendingInvokes(r.Context(), thread, &run[len(run)-1])
+	if err != nil {
+		return err
+	}
+
+	for _, output := range body.ToolOutputs {
+		i := slices.IndexFunc(invokes, func(invoke v1.InvokeTool) bool {
+			return invoke.Spec.ToolCall.ID == output.ToolCallID
+		})
+		if i == -1 {
+			return badRequest("tool call %s is not waiting for output", output.ToolCallID)
+		}
+
+		text := string(output.Output)
+		if err := json.Unmarshal(output.Output, &text); err != nil {
+			// Not a string, pass on the JSON
+			text = string(output.Output)
+		}
+
+		invoke := &invokes[i]
+		patch := kclient.MergeFrom(invoke.DeepCopy())
+		invoke.Spec.Output = &text
+		if err := h.client.Patch(r.Context(), invoke, patch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Handler) cancel(ctx context.Context, run []v1.Message) error {
+	last := &run[len(run)-1]
+	if last.Status.Phase.Done() && !runActive(last) {
+		return badRequest("run %s is not active", run[0].Name)
+	}
+	patch := kclient.MergeFrom(last.DeepCopy())
+	last.Spec.Cancel = true
+	return h.client.Patch(ctx, last, patch)
+}
+
+// pendingInvokes returns the calls of client functions by msg that are waiting for their output
+func (h *Handler) pendingInvokes(ctx context.Context, thread *v1.Thread, msg *v1.Message) (result []v1.InvokeTool, _ error) {
+	if msg.Status.Phase != v1.PhaseAwaitingTools {
+		return nil, nil
+	}
+
+	var assistant v1.Assistant
+	if err := h.client.Get(ctx, router.Key(thread.Namespace, thread.Spec.AssistantName), &assistant); apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, name := range msg.Status.InvokeToolNames {
+		var invoke v1.InvokeTool
+		if err := h.client.Get(ctx, router.Key(msg.Namespace, name), &invoke); apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if function := assistant.Function(invoke.Spec.ToolCall.Function.Name); function != nil && function.Client &&
+			invoke.Spec.Output == nil {
+			result = append(result, invoke)
+		}
+	}
+
+	return result, nil
+}
+
+// splitRuns splits the messages of a thread into runs
+func splitRuns(msgs []v1.Message) (result [][]v1.Message) {
+	var run []v1.Message
+	for i, msg := range msgs {
+		switch {
+		case msg.Status.Message.Role == v1.RoleTypeUser:
+			if len(run) > 0 {
+				result = append(result, run)
+			}
+			run = nil
+		case msg.Spec.Input.Completion && i > 0 && msgs[i-1].Status.Message.Role == v1.RoleTypeUser:
+			run = []v1.Message{msg}
+		case len(run) > 0:
+			run = append(run, msg)
+		}
+	}
+	if len(run) > 0 {
+		result = append(result, run)
+	}
+	return result
+}
+
+func (h *Handler) toRun(ctx context.Context, thread *v1.Thread, run []v1.Message) (openai.Run, error) {
+	var (
+		first  = run[0]
+		last   = run[len(run)-1]
+		result = openai.Run{
+			ID:          first.Name,
+			Object:      "thread.run",
+			CreatedAt:   first.CreationTimestamp.Unix(),
+			ThreadID:    thread.Name,
+			AssistantID: thread.Spec.AssistantName,
+			Tools:       []openai.Tool{},
+			FileIDS:     []string{},
+			Metadata:    metadata(first.ObjectMeta),
+		}
+		assistant v1.Assistant
+	)
+
+	if err := h.client.Get(ctx, router.Key(thread.Namespace, thread.Spec.AssistantName), &assistant); err == nil {
+		result.Model = assistant.Spec.Model
+		result.Instructions = assistant.Spec.Instructions
+		for _, tool := range toTools(assistant.Spec.Tools) {
+			result.Tools = append(result.Tools, openai.Tool{
+				Type:     openai.ToolTypeFunction,
+				Function: tool.Function,
+			})
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return result, err
+	}
+
+	for _, msg := range run {
+		if msg.Status.Usage != nil {
+			result.Usage.PromptTokens += msg.Status.Usage.PromptTokens
+			result.Usage.CompletionTokens += msg.Status.Usage.CompletionTokens
+			result.Usage.TotalTokens += msg.Status.Usage.TotalTokens
+		}
+		if msg.Spec.Cancel && !last.Status.Phase.Done() {
+			result.Status = openai.RunStatusCancelling
+		}
+	}
+
+	if len(first.Status.Message.Content) > 0 || first.Status.InProgress {
+		result.StartedAt = z.Pointer(first.CreationTimestamp.Unix())
+	}
+
+	if result.Status != "" {
+		return result, nil
+	}
+
+	switch last.Status.Phase {
+	case v1.PhaseFailed:
+		result.Status = openai.RunStatusFailed
+		result.LastError = &openai.RunLastError{
+			Code:    openai.RunErrorServerError,
+			Message: failure(&last),
+		}
+	case v1.PhaseCancelled:
+		result.Status = openai.RunStatusCancelled
+	case v1.PhaseComplete:
+		if last.Status.Message.Role == v1.RoleTypeAssistant && !last.Status.Message.IsToolCall() {
+			result.Status = openai.RunStatusCompleted
+		} else {
+			// Waiting for the next completion
+			result.Status = openai.RunStatusInProgress
+		}
+	case v1.PhaseAwaitingTools:
+		invokes, err := h.pendingInvokes(ctx, thread, &last)
+		if err != nil {
+			return result, err
+		}
+		if len(invokes) == 0 {
+			result.Status = openai.RunStatusInProgress
+			break
+		}
+		result.Status = openai.RunStatusRequiresAction
+		result.RequiredAction = &openai.RunRequiredAction{
+			Type:              openai.RequiredActionTypeSubmitToolOutputs,
+			SubmitToolOutputs: &openai.SubmitToolOutputs{},
+		}
+		for _, invoke := range invokes {
+			result.RequiredAction.SubmitToolOutputs.ToolCalls = append(result.RequiredAction.SubmitToolOutputs.ToolCalls,
+				openai.ToolCall{
+					ID:   invoke.Spec.ToolCall.ID,
+					Type: openai.ToolTypeFunction,
+					Function: openai.FunctionCall{
+						Name:      invoke.Spec.ToolCall.Function.Name,
+						Arguments: invoke.Spec.ToolCall.Function.Arguments,
+					},
+				})
+		}
+	case v1.PhasePending:
+		if len(run) == 1 && result.StartedAt == nil {
+			result.Status = openai.RunStatusQueued
+		} else {
+			result.Status = openai.RunStatusInProgress
+		}
+	default:
+		result.Status = openai.RunStatusInProgress
+	}
+
+	return result, nil
+}
+
+// failure is the message of the condition that failed msg
+func failure(msg *v1.Message) string {
+	for _, cond := range msg.Status.Conditions {
+		if cond.Type == "Controller" && cond.Status == "False" {
+			return cond.Message
+		}
+	}
+	return "the run failed"
+}
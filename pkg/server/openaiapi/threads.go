@@ -0,0 +1,396 @@
+package openaiapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/baaah/pkg/name"
+	"github.com/acorn-io/baaah/pkg/router"
+	"github.com/acorn-io/z"
+	"github.com/sashabaranov/go-openai"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type threadRequest struct {
+	Messages []messageRequest `json:"messages,omitempty"`
+	Metadata map[string]any   `json:"metadata,omitempty"`
+}
+
+type messageRequest struct {
+	Role string `json:"role,omitempty"`
+	// Content is either a string or a list of content parts
+	Content  json.RawMessage `json:"content,omitempty"`
+	Metadata map[string]any  `json:"metadata,omitempty"`
+}
+
+type contentPart struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL *struct {
+		URL    string `json:"url"`
+		Detail string `json:"detail,omitempty"`
+	} `json:"image_url,omitempty"`
+}
+
+func (h *Handler) createThread(r *request) error {
+	if r.Method != http.MethodPost {
+		return errMethodNotAllowed
+	}
+
+	var body threadRequest
+	if err := r.decode(&body); err != nil {
+		return err
+	}
+
+	thread := &v1.Thread{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "thread-",
+			Namespace:    h.namespace,
+		},
+	}
+	if err := setMetadata(&thread.ObjectMeta, body.Metadata); err != nil {
+		return err
+	}
+	if err := h.client.Create(r.Context(), thread); err != nil {
+		return err
+	}
+
+	for _, msg := range body.Messages {
+		input, err := msg.input()
+		if err != nil {
+			return err
+		}
+		if _, err := h.addMessage(r.Context(), thread, input, msg.Metadata); err != nil {
+			return err
+		}
+	}
+
+	return r.write(toThread(thread))
+}
+
+func (h *Handler) thread(r *request, id string) error {
+	thread, err := h.getThread(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return r.write(toThread(thread))
+	case http.MethodPost:
+		var body threadRequest
+		if err := r.decode(&body); err != nil {
+			return err
+		}
+		if err := setMetadata(&thread.ObjectMeta, body.Metadata); err != nil {
+			return err
+		}
+		if err := h.client.Update(r.Context(), thread); err != nil {
+			return err
+		}
+		return r.write(toThread(thread))
+	case http.MethodDelete:
+		if err := h.client.Delete(r.Context(), thread); err != nil {
+			return err
+		}
+		return r.write(openai.ThreadDeleteResponse{
+			ID:      thread.Name,
+			Object:  "thread.deleted",
+			Deleted: true,
+		})
+	}
+	return errMethodNotAllowed
+}
+
+func (h *Handler) messages(r *request, threadID string) error {
+	thread, err := h.getThread(r.Context(), threadID)
+	if err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		msgs, err := h.activeMessages(r.Context(), thread)
+		if err != nil {
+			return err
+		}
+
+		var (
+			result []openai.Message
+			runID  = r.URL.Query().Get("run_id")
+		)
+		for _, msg := range toMessages(thread, msgs) {
+			if runID == "" || (msg.RunID != nil && *msg.RunID == runID) {
+				result = append(result, msg)
+			}
+		}
+
+		page, hasMore, err := paginate(r, result, func(m openai.Message) string { return m.ID })
+		if err != nil {
+			return err
+		}
+		if page == nil {
+			page = []openai.Message{}
+		}
+		return writeList(r, page, hasMore, func(m openai.Message) string { return m.ID })
+	case http.MethodPost:
+		var body messageRequest
+		if err := r.decode(&body); err != nil {
+			return err
+		}
+		input, err := body.input()
+		if err != nil {
+			return err
+		}
+		msg, err := h.addMessage(r.Context(), thread, input, body.Metadata)
+		if err != nil {
+			return err
+		}
+		return r.write(toMessages(thread, []v1.Message{*msg})[0])
+	}
+	return errMethodNotAllowed
+}
+
+func (h *Handler) message(r *request, threadID, id string) error {
+	if r.Method != http.MethodGet {
+		return errMethodNotAllowed
+	}
+
+	thread, err := h.getThread(r.Context(), threadID)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := h.activeMessages(r.Context(), thread)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range toMessages(thread, msgs) {
+		if msg.ID == id {
+			return r.write(msg)
+		}
+	}
+	return errNotFound
+}
+
+func (h *Handler) getThread(ctx context.Context, id string) (*v1.Thread, error) {
+	var thread v1.Thread
+	return &thread, h.client.Get(ctx, router.Key(h.namespace, id), &thread)
+}
+
+// activeMessages returns the messages on the active branch of thread
+func (h *Handler) activeMessages(ctx context.Context, thread *v1.Thread) (result []v1.Message, _ error) {
+	next := thread.Spec.StartMessageName
+	for next != "" {
+		var msg v1.Message
+		if err := h.client.Get(ctx, router.Key(thread.Namespace, next), &msg); apierrors.IsNotFound(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, msg)
+		next = msg.Status.NextMessageName
+	}
+	return result, nil
+}
+
+// addMessage adds a message to the end of the thread. Messages are added with spec.more, so that nothing is
+// generated until a run is created.
+func (h *Handler) addMessage(ctx context.Context, thread *v1.Thread, input v1.MessageInput, md map[string]any) (*v1.Message, error) {
+	msg := &v1.Message{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: thread.Namespace,
+		},
+		Spec: v1.MessageSpec{
+			Input: input,
+			More:  true,
+		},
+	}
+
+	if err := setMetadata(&msg.ObjectMeta, md); err != nil {
+		return nil, err
+	}
+
+	msgs, err := h.activeMessages(ctx, thread)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msgs) == 0 {
+		// The thread has to point at its first message before the message is created for the message to find
+		// its thread
+		msg.Name = name.SafeConcatName(thread.Name, "start")
+		if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := h.client.Get(ctx, kclient.ObjectKeyFromObject(thread), thread); err != nil {
+				return err
+			}
+			thread.Spec.StartMessageName = msg.Name
+			return h.client.Update(ctx, thread)
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		last := msgs[len(msgs)-1]
+		if runActive(&last) {
+			return nil, badRequest("can't add messages to thread %s while a run is active", thread.Name)
+		}
+		msg.GenerateName = "msg-"
+		msg.Spec.ParentMessageName = last.Name
+	}
+
+	if err := h.client.Create(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	// Wait for the controller to add the message to the thread, so that the next message follows it
+	return msg, waitFor(ctx, func(ctx context.Context) (bool, error) {
+		if err := h.client.Get(ctx, kclient.ObjectKeyFromObject(msg), msg); err != nil {
+			return false, err
+		}
+		if msg.Status.ThreadName != thread.Name || msg.Status.Phase == "" {
+			return false, nil
+		}
+		if msg.Spec.ParentMessageName == "" {
+			return true, nil
+		}
+		var parent v1.Message
+		if err := h.client.Get(ctx, router.Key(msg.Namespace, msg.Spec.ParentMessageName), &parent); err != nil {
+			return false, err
+		}
+		return parent.Status.NextMessageName == msg.Name, nil
+	})
+}
+
+func (in messageRequest) input() (result v1.MessageInput, _ error) {
+	switch in.Role {
+	case "", openai.ChatMessageRoleUser:
+	case openai.ChatMessageRoleAssistant:
+		result.Role = v1.RoleTypeAssistant
+	default:
+		return result, badRequest("role must be user or assistant")
+	}
+
+	content, err := parseContent(in.Content)
+	if err != nil {
+		return result, err
+	}
+	result.Content = content
+	return result, nil
+}
+
+func parseContent(data json.RawMessage) ([]v1.ContentPart, error) {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		if text == "" {
+			return nil, badRequest("content is required")
+		}
+		return v1.Text(text), nil
+	}
+
+	var (
+		parts  []contentPart
+		result []v1.ContentPart
+	)
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return nil, badRequest("content must be a string or a list of content parts")
+	}
+	for _, part := range parts {
+		switch {
+		case part.Type == "text":
+			result = append(result, v1.ContentPart{Text: part.Text})
+		case part.Type == "image_url" && part.ImageURL != nil:
+			result = append(result, v1.ContentPart{
+				Image: &v1.ChatMessageImageURL{
+					URL:    part.ImageURL.URL,
+					Detail: v1.ImageURLDetail(part.ImageURL.Detail),
+				},
+			})
+		default:
+			return nil, badRequest("content parts of type %s are not supported", part.Type)
+		}
+	}
+	if len(result) == 0 {
+		return nil, badRequest("content is required")
+	}
+	return result, nil
+}
+
+func toThread(thread *v1.Thread) openai.Thread {
+	return openai.Thread{
+		ID:        thread.Name,
+		Object:    "thread",
+		CreatedAt: thread.CreationTimestamp.Unix(),
+		Metadata:  metadata(thread.ObjectMeta),
+	}
+}
+
+// toMessages converts the user messages and the text responses of the assistant in msgs. Tool calls and their
+// results are part of runs rather than messages in the OpenAI API.
+func toMessages(thread *v1.Thread, msgs []v1.Message) (result []openai.Message) {
+	var (
+		runID    string
+		lastRole v1.RoleType
+	)
+
+	for _, msg := range msgs {
+		role := msg.Status.Message.Role
+		switch {
+		case role == v1.RoleTypeUser:
+			runID = ""
+		case msg.Spec.Input.Completion && lastRole == v1.RoleTypeUser:
+			runID = msg.Name
+		}
+		lastRole = role
+
+		var content []openai.MessageContent
+		for _, part := range msg.Status.Message.Content {
+			if part.Text != "" {
+				content = append(content, openai.MessageContent{
+					Type: "text",
+					Text: &openai.MessageText{
+						Value:       part.Text,
+						Annotations: []any{},
+					},
+				})
+			}
+		}
+		if len(content) == 0 || (role != v1.RoleTypeUser && role != v1.RoleTypeAssistant) {
+			continue
+		}
+
+		message := openai.Message{
+			ID:        msg.Name,
+			Object:    "thread.message",
+			CreatedAt: int(msg.CreationTimestamp.Unix()),
+			ThreadID:  thread.Name,
+			Role:      string(role),
+			Content:   content,
+			FileIds:   []string{},
+			Metadata:  metadata(msg.ObjectMeta),
+		}
+		if role == v1.RoleTypeAssistant && thread.Spec.AssistantName != "" {
+			message.AssistantID = &thread.Spec.AssistantName
+		}
+		if runID != "" {
+			message.RunID = z.Pointer(runID)
+		}
+		result = append(result, message)
+	}
+
+	return result
+}
+
+// runActive is true if a run of the thread that ends with msg is not done
+func runActive(msg *v1.Message) bool {
+	if !msg.Status.Phase.Done() {
+		return true
+	}
+	// A complete message that is not a response of the assistant is waiting for one, unless more messages follow
+	return msg.Status.Phase == v1.PhaseComplete && msg.Status.Message.Role != v1.RoleTypeAssistant && !msg.Spec.More
+}
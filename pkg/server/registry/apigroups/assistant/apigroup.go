@@ -3,7 +3,10 @@ package assistant
 import (
 	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
 	"github.com/acorn-io/assistant-runtime/pkg/scheme"
+	"github.com/acorn-io/assistant-runtime/pkg/server/registry/apigroups/assistant/credentials"
 	"github.com/acorn-io/assistant-runtime/pkg/server/registry/apigroups/assistant/images"
+	"github.com/acorn-io/assistant-runtime/pkg/server/registry/apigroups/assistant/messages"
+	"github.com/acorn-io/assistant-runtime/pkg/server/registry/apigroups/assistant/threads"
 	"github.com/acorn-io/assistant-runtime/pkg/server/registry/generic"
 	"github.com/acorn-io/assistant-runtime/pkg/server/services"
 	"github.com/acorn-io/baaah/pkg/typed"
@@ -17,16 +20,17 @@ func Stores(services *services.Services) (map[string]rest.Storage, error) {
 	result := map[string]rest.Storage{}
 
 	var generics = map[string]kclient.Object{
-		"assistants":  &v1.Assistant{},
-		"caches":      &v1.Cache{},
-		"invoketools": &v1.InvokeTool{},
-		"messages":    &v1.Message{},
-		"threads":     &v1.Thread{},
-		"images":      &v1.Image{},
+		"assistants":     &v1.Assistant{},
+		"caches":         &v1.Cache{},
+		"invoketools":    &v1.InvokeTool{},
+		"messages":       &v1.Message{},
+		"threads":        &v1.Thread{},
+		"images":         &v1.Image{},
+		"modelproviders": &v1.ModelProvider{},
 	}
 
 	for _, name := range typed.SortedKeys(generics) {
-		store, statusStore, err := generic.NewStore(services.DB, generics[name])
+		store, statusStore, err := generic.NewStore(services.DB, generics[name], columns[name]...)
 		if err != nil {
 			return nil, err
 		}
@@ -35,10 +39,27 @@ func Stores(services *services.Services) (map[string]rest.Storage, error) {
 		result[name+"/status"] = statusStore
 	}
 
+	credentialStore, credentialStatusStore, err := credentials.NewStore(services.DB)
+	if err != nil {
+		return nil, err
+	}
+	result["credentials"] = credentialStore
+	result["credentials/status"] = credentialStatusStore
+
 	result["images/serve"] = &images.Serve{
 		Client: services.Client,
 	}
 
+	result["messages/regenerate"] = &messages.Regenerate{
+		Client: services.Client,
+	}
+
+	result["messages/stream"] = messages.NewStream(services.Client)
+
+	result["threads/fork"] = &threads.Fork{
+		Client: services.Client,
+	}
+
 	return result, nil
 }
 
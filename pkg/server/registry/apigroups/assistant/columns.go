@@ -0,0 +1,82 @@
+package assistant
+
+import (
+	"strconv"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/server/registry/generic"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// columns are the printer columns of the stores, in addition to the name and age
+var columns = map[string][]generic.Column{
+	"invoketools": {
+		{
+			Name:        "Tool",
+			Description: "Name of the called function or assistant",
+			Value: func(obj kclient.Object) string {
+				return obj.(*v1.InvokeTool).Spec.ToolCall.Function.Name
+			},
+		},
+		{
+			Name:        "Phase",
+			Description: "Phase of the tool call",
+			Value: func(obj kclient.Object) string {
+				return string(obj.(*v1.InvokeTool).Status.Phase)
+			},
+		},
+	},
+	"messages": {
+		{
+			Name:        "Thread",
+			Description: "Thread of the message",
+			Value: func(obj kclient.Object) string {
+				return obj.(*v1.Message).Status.ThreadName
+			},
+		},
+		{
+			Name:        "Role",
+			Description: "Role of the message",
+			Value: func(obj kclient.Object) string {
+				return string(obj.(*v1.Message).Status.Message.Role)
+			},
+		},
+		{
+			Name:        "Phase",
+			Description: "Phase of the message",
+			Value: func(obj kclient.Object) string {
+				return string(obj.(*v1.Message).Status.Phase)
+			},
+		},
+	},
+	"threads": {
+		{
+			Name:        "Assistant",
+			Description: "Assistant of the thread",
+			Value: func(obj kclient.Object) string {
+				return obj.(*v1.Thread).Spec.AssistantName
+			},
+		},
+		{
+			Name:        "Phase",
+			Description: "Phase of the last message of the thread",
+			Value: func(obj kclient.Object) string {
+				return string(obj.(*v1.Thread).Status.Phase)
+			},
+		},
+		{
+			Name:        "Messages",
+			Description: "Number of messages in the thread",
+			Value: func(obj kclient.Object) string {
+				return strconv.Itoa(obj.(*v1.Thread).Status.MessageCount)
+			},
+		},
+		{
+			Name:        "Description",
+			Description: "Title of the thread, generated from its first messages",
+			Value: func(obj kclient.Object) string {
+				return obj.(*v1.Thread).Status.Description
+			},
+		},
+	},
+}
@@ -0,0 +1,133 @@
+package credentials
+
+import (
+	"context"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/scheme"
+	"github.com/acorn-io/assistant-runtime/pkg/server/services"
+	"github.com/acorn-io/mink/pkg/db"
+	"github.com/acorn-io/mink/pkg/stores"
+	"github.com/acorn-io/mink/pkg/strategy"
+	"github.com/acorn-io/mink/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage"
+)
+
+// NewStore returns the stores of credentials. The values of their data are only returned to the admin user, which
+// the controller authenticates as to read the API keys of model providers, and are blank for everyone else.
+func NewStore(db *db.Factory) (rest.Storage, rest.Storage, error) {
+	storage, err := db.NewDBStrategy(&v1.Credential{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := &redactStrategy{
+		CompleteStrategy: storage,
+	}
+	return stores.NewComplete(scheme.Scheme, s), stores.NewStatus(scheme.Scheme, s), nil
+}
+
+// redactStrategy blanks the values of the data of credentials it returns to users other than the admin user. A
+// blank value in an update keeps the stored value, so that an object that was read can be written back.
+type redactStrategy struct {
+	strategy.CompleteStrategy
+}
+
+func (r *redactStrategy) Get(ctx context.Context, namespace, name string) (types.Object, error) {
+	obj, err := r.CompleteStrategy.Get(ctx, namespace, name)
+	return redact(ctx, obj), err
+}
+
+func (r *redactStrategy) List(ctx context.Context, namespace string, opts storage.ListOptions) (types.ObjectList, error) {
+	list, err := r.CompleteStrategy.List(ctx, namespace, opts)
+	if credentials, ok := list.(*v1.CredentialList); ok && credentials != nil {
+		for i := range credentials.Items {
+			redact(ctx, &credentials.Items[i])
+		}
+	}
+	return list, err
+}
+
+func (r *redactStrategy) Watch(ctx context.Context, namespace string, opts storage.ListOptions) (<-chan watch.Event, error) {
+	events, err := r.CompleteStrategy.Watch(ctx, namespace, opts)
+	if err != nil || isAdmin(ctx) {
+		return events, err
+	}
+
+	result := make(chan watch.Event)
+	go func() {
+		defer close(result)
+		for event := range events {
+			if obj, ok := event.Object.(types.Object); ok {
+				event.Object = redact(ctx, obj.DeepCopyObject().(types.Object))
+			}
+			result <- event
+		}
+	}()
+	return result, nil
+}
+
+func (r *redactStrategy) Create(ctx context.Context, obj types.Object) (types.Object, error) {
+	obj, err := r.CompleteStrategy.Create(ctx, obj)
+	return redact(ctx, obj), err
+}
+
+func (r *redactStrategy) Update(ctx context.Context, obj types.Object) (types.Object, error) {
+	if err := r.restore(ctx, obj); err != nil {
+		return nil, err
+	}
+	obj, err := r.CompleteStrategy.Update(ctx, obj)
+	return redact(ctx, obj), err
+}
+
+func (r *redactStrategy) UpdateStatus(ctx context.Context, obj types.Object) (types.Object, error) {
+	obj, err := r.CompleteStrategy.UpdateStatus(ctx, obj)
+	return redact(ctx, obj), err
+}
+
+// Delete writes the object that was read with Get, which has blank values for users other than the admin user
+func (r *redactStrategy) Delete(ctx context.Context, obj types.Object) (types.Object, error) {
+	if err := r.restore(ctx, obj); err != nil {
+		return nil, err
+	}
+	obj, err := r.CompleteStrategy.Delete(ctx, obj)
+	return redact(ctx, obj), err
+}
+
+// restore sets the blank values of the data of obj to the stored values, if the user of ctx is not the admin user
+func (r *redactStrategy) restore(ctx context.Context, obj types.Object) error {
+	credential, ok := obj.(*v1.Credential)
+	if !ok || isAdmin(ctx) {
+		return nil
+	}
+	existing, err := r.CompleteStrategy.Get(ctx, credential.Namespace, credential.Name)
+	if err != nil {
+		return err
+	}
+	for k, v := range credential.Spec.Data {
+		if v == "" {
+			credential.Spec.Data[k] = existing.(*v1.Credential).Spec.Data[k]
+		}
+	}
+	return nil
+}
+
+func isAdmin(ctx context.Context) bool {
+	u, ok := request.UserFrom(ctx)
+	return ok && u.GetName() == services.AdminUser
+}
+
+// redact blanks the values of the data of obj, if it is a credential and the user of ctx is not the admin user
+func redact(ctx context.Context, obj types.Object) types.Object {
+	credential, ok := obj.(*v1.Credential)
+	if !ok || credential == nil || isAdmin(ctx) {
+		return obj
+	}
+	for k := range credential.Spec.Data {
+		credential.Spec.Data[k] = ""
+	}
+	return obj
+}
@@ -0,0 +1,115 @@
+package messages
+
+import (
+	"sync"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+)
+
+const subscriberBuffer = 1024
+
+type event struct {
+	name string
+	data any
+}
+
+// hub passes the deltas published for messages being generated to the clients streaming them
+type hub struct {
+	lock    sync.Mutex
+	streams map[string]*stream
+}
+
+type stream struct {
+	// text is everything published since the generation started
+	text        string
+	publishers  int
+	subscribers map[chan event]struct{}
+}
+
+func newHub() *hub {
+	return &hub{
+		streams: map[string]*stream{},
+	}
+}
+
+func (h *hub) get(key string) *stream {
+	s, ok := h.streams[key]
+	if !ok {
+		s = &stream{
+			subscribers: map[chan event]struct{}{},
+		}
+		h.streams[key] = s
+	}
+	return s
+}
+
+// cleanup forgets the stream of key once nothing uses it
+func (h *hub) cleanup(key string, s *stream) {
+	if s.publishers == 0 && len(s.subscribers) == 0 {
+		delete(h.streams, key)
+	}
+}
+
+// subscribe returns the text published so far and the events that follow it. The events are closed if the
+// subscriber falls behind.
+func (h *hub) subscribe(key string) (string, <-chan event, func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	s := h.get(key)
+	events := make(chan event, subscriberBuffer)
+	s.subscribers[events] = struct{}{}
+
+	return s.text, events, func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		if _, ok := s.subscribers[events]; ok {
+			delete(s.subscribers, events)
+			close(events)
+		}
+		h.cleanup(key, s)
+	}
+}
+
+// begin starts a new generation of the message, replacing anything published before
+func (h *hub) begin(key string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	s := h.get(key)
+	s.publishers++
+	s.text = ""
+	s.send(event{name: "reset", data: struct{}{}})
+}
+
+func (h *hub) end(key string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	s := h.get(key)
+	s.publishers--
+	if s.publishers == 0 {
+		s.text = ""
+	}
+	h.cleanup(key, s)
+}
+
+func (h *hub) publish(key string, delta v1.MessageDelta) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	s := h.get(key)
+	s.text += delta.Text
+	s.send(event{name: "delta", data: delta})
+}
+
+func (s *stream) send(e event) {
+	for events := range s.subscribers {
+		select {
+		case events <- e:
+		default:
+			delete(s.subscribers, events)
+			close(events)
+		}
+	}
+}
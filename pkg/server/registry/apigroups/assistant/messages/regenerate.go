@@ -0,0 +1,103 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/scheme"
+	"github.com/acorn-io/mink/pkg/strategy"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/endpoints/handlers/negotiation"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/util/retry"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const createTimeout = 30 * time.Second
+
+// Regenerate asks for another response of the assistant to the parent of a response, generated with a different
+// seed. The thread of the response is switched to the new response, and the new response is returned.
+type Regenerate struct {
+	strategy.DestroyAdapter
+
+	Client kclient.Client
+}
+
+func (r *Regenerate) New() runtime.Object {
+	return &v1.Message{}
+}
+
+func (r *Regenerate) Connect(ctx context.Context, id string, options runtime.Object, _ rest.Responder) (http.Handler, error) {
+	ns, _ := request.NamespaceFrom(ctx)
+	msg := &v1.Message{}
+	if err := r.Client.Get(ctx, kclient.ObjectKey{Namespace: ns, Name: id}, msg); err != nil {
+		return nil, err
+	}
+	if !msg.Spec.Input.Completion || msg.Spec.ParentMessageName == "" {
+		return nil, apierrors.NewBadRequest("only responses of the assistant can be regenerated")
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		response, err := r.regenerate(req.Context(), msg)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, scheme.Codecs, v1.SchemeGroupVersion, rw, req)
+			return
+		}
+		responsewriters.WriteObjectNegotiated(scheme.Codecs, negotiation.DefaultEndpointRestrictions, v1.SchemeGroupVersion, rw, req, http.StatusCreated, response, false)
+	}), nil
+}
+
+func (r *Regenerate) regenerate(ctx context.Context, msg *v1.Message) (*v1.Message, error) {
+	var parent v1.Message
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Client.Get(ctx, kclient.ObjectKey{Namespace: msg.Namespace, Name: msg.Spec.ParentMessageName}, &parent); err != nil {
+			return err
+		}
+		parent.Spec.Alternatives++
+		return r.Client.Update(ctx, &parent)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responseName := parent.ResponseName(parent.Spec.Alternatives)
+
+	if msg.Status.ThreadName != "" {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			var thread v1.Thread
+			if err := r.Client.Get(ctx, kclient.ObjectKey{Namespace: msg.Namespace, Name: msg.Status.ThreadName}, &thread); err != nil {
+				return err
+			}
+			thread.Spec.ActiveMessageName = responseName
+			return r.Client.Update(ctx, &thread)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The response is created by the controller
+	response := &v1.Message{}
+	err = wait.PollUntilContextTimeout(ctx, 250*time.Millisecond, createTimeout, true, func(ctx context.Context) (bool, error) {
+		err := r.Client.Get(ctx, kclient.ObjectKey{Namespace: msg.Namespace, Name: responseName}, response)
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	return response, err
+}
+
+func (r *Regenerate) NewConnectOptions() (runtime.Object, bool, string) {
+	return &v1.NoOptions{}, false, ""
+}
+
+func (r *Regenerate) ConnectMethods() []string {
+	return []string{http.MethodPost}
+}
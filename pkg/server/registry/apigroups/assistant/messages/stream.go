@@ -0,0 +1,193 @@
+package messages
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/scheme"
+	"github.com/acorn-io/assistant-runtime/pkg/server/services"
+	"github.com/acorn-io/mink/pkg/strategy"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	checkpointInterval = time.Second
+	maxDeltaSize       = 1024 * 1024
+)
+
+// Stream sends the content of a message while it is generated as server-sent events. A GET receives a "delta"
+// event with the content so far, a "delta" event for each piece of content added after that, and a "done" event
+// with the message once it is generated. A "reset" event means the generation started over, and the content
+// received so far should be dropped.
+//
+// The controller publishes the content with a POST of newline delimited MessageDelta objects while it generates
+// the message. Only the admin user, which the controller authenticates as, can publish.
+type Stream struct {
+	strategy.DestroyAdapter
+
+	Client kclient.Client
+	hub    *hub
+}
+
+func NewStream(client kclient.Client) *Stream {
+	return &Stream{
+		Client: client,
+		hub:    newHub(),
+	}
+}
+
+func (s *Stream) New() runtime.Object {
+	return &v1.NoOptions{}
+}
+
+func (s *Stream) Connect(ctx context.Context, id string, _ runtime.Object, _ rest.Responder) (http.Handler, error) {
+	ns, _ := request.NamespaceFrom(ctx)
+	msg := &v1.Message{}
+	if err := s.Client.Get(ctx, kclient.ObjectKey{Namespace: ns, Name: id}, msg); err != nil {
+		return nil, err
+	}
+
+	key := ns + "/" + id
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			if u, ok := request.UserFrom(req.Context()); !ok || u.GetName() != services.AdminUser {
+				responsewriters.ErrorNegotiated(apierrors.NewForbidden(v1.SchemeGroupVersion.WithResource("messages").GroupResource(),
+					id, fmt.Errorf("only the controller can publish the content of messages")), scheme.Codecs, v1.SchemeGroupVersion, rw, req)
+				return
+			}
+			s.receive(rw, req, key)
+		} else {
+			s.send(rw, req, msg)
+		}
+	}), nil
+}
+
+func (s *Stream) NewConnectOptions() (runtime.Object, bool, string) {
+	return &v1.NoOptions{}, false, ""
+}
+
+func (s *Stream) ConnectMethods() []string {
+	return []string{http.MethodGet, http.MethodPost}
+}
+
+// receive publishes the deltas posted by the controller
+func (s *Stream) receive(rw http.ResponseWriter, req *http.Request, key string) {
+	s.hub.begin(key)
+	defer s.hub.end(key)
+
+	scanner := bufio.NewScanner(req.Body)
+	scanner.Buffer(nil, maxDeltaSize)
+	for scanner.Scan() {
+		var delta v1.MessageDelta
+		if err := json.Unmarshal(scanner.Bytes(), &delta); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.hub.publish(key, delta)
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// send streams the content of msg to a client until it is generated
+func (s *Stream) send(rw http.ResponseWriter, req *http.Request, msg *v1.Message) {
+	text, events, unsubscribe := s.hub.subscribe(msg.Namespace + "/" + msg.Name)
+	defer unsubscribe()
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.WriteHeader(http.StatusOK)
+
+	if generated(msg) {
+		_ = s.write(rw, "delta", v1.MessageDelta{Text: textOf(msg)})
+		_ = s.writeDone(rw, msg)
+		return
+	}
+
+	if text == "" {
+		// Nothing was published yet, start from the last checkpoint
+		text = textOf(msg)
+	}
+	if text != "" {
+		if err := s.write(rw, "delta", v1.MessageDelta{Text: text}); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(checkpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				// Fell behind, the client has to reconnect
+				return
+			}
+			if err := s.write(rw, e.name, e.data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := s.Client.Get(req.Context(), kclient.ObjectKeyFromObject(msg), msg); err != nil {
+				_ = s.write(rw, "error", responsewriters.ErrorToAPIStatus(err))
+				return
+			}
+			if generated(msg) {
+				_ = s.writeDone(rw, msg)
+				return
+			}
+		}
+	}
+}
+
+func (s *Stream) writeDone(rw http.ResponseWriter, msg *v1.Message) error {
+	data, err := runtime.Encode(scheme.Codecs.LegacyCodec(v1.SchemeGroupVersion), msg)
+	if err != nil {
+		return err
+	}
+	return s.write(rw, "done", json.RawMessage(data))
+}
+
+func (s *Stream) write(rw http.ResponseWriter, name string, data any) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", name, bytes); err != nil {
+		return err
+	}
+	if flusher, ok := rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// generated is true once msg is no longer being generated
+func generated(msg *v1.Message) bool {
+	switch msg.Status.Phase {
+	case v1.PhaseFailed, v1.PhaseCancelled:
+		return true
+	}
+	return msg.Status.RunAfter == nil && !msg.Status.InProgress && msg.Status.Message.HasContent()
+}
+
+func textOf(msg *v1.Message) string {
+	var texts []string
+	for _, content := range msg.Status.Message.Content {
+		texts = append(texts, content.Text)
+	}
+	return strings.Join(texts, "")
+}
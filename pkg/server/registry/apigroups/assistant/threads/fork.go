@@ -0,0 +1,188 @@
+package threads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+	"github.com/acorn-io/assistant-runtime/pkg/scheme"
+	"github.com/acorn-io/baaah/pkg/name"
+	"github.com/acorn-io/mink/pkg/strategy"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/handlers/negotiation"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Fork copies the messages of a thread up to a message into a new thread. The copies keep the content of the
+// original messages, so responses and tool calls are not generated or invoked again.
+type Fork struct {
+	strategy.DestroyAdapter
+
+	Client kclient.Client
+}
+
+func (f *Fork) New() runtime.Object {
+	return &v1.ThreadFork{}
+}
+
+func (f *Fork) Connect(ctx context.Context, id string, _ runtime.Object, _ rest.Responder) (http.Handler, error) {
+	ns, _ := request.NamespaceFrom(ctx)
+	thread := &v1.Thread{}
+	if err := f.Client.Get(ctx, kclient.ObjectKey{Namespace: ns, Name: id}, thread); err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fork := &v1.ThreadFork{}
+		if req.ContentLength != 0 {
+			if err := json.NewDecoder(req.Body).Decode(fork); err != nil {
+				responsewriters.ErrorNegotiated(apierrors.NewBadRequest(err.Error()), scheme.Codecs, v1.SchemeGroupVersion, rw, req)
+				return
+			}
+		}
+
+		if err := f.fork(req.Context(), thread, fork); err != nil {
+			responsewriters.ErrorNegotiated(err, scheme.Codecs, v1.SchemeGroupVersion, rw, req)
+			return
+		}
+
+		fork.Name = thread.Name
+		fork.Namespace = thread.Namespace
+		responsewriters.WriteObjectNegotiated(scheme.Codecs, negotiation.DefaultEndpointRestrictions, v1.SchemeGroupVersion, rw, req, http.StatusCreated, fork, false)
+	}), nil
+}
+
+func (f *Fork) NewConnectOptions() (runtime.Object, bool, string) {
+	return &v1.NoOptions{}, false, ""
+}
+
+func (f *Fork) ConnectMethods() []string {
+	return []string{http.MethodPost}
+}
+
+func (f *Fork) fork(ctx context.Context, thread *v1.Thread, fork *v1.ThreadFork) error {
+	if fork.Spec.AssistantName == "" {
+		fork.Spec.AssistantName = thread.Spec.AssistantName
+	} else if err := f.Client.Get(ctx, kclient.ObjectKey{Namespace: thread.Namespace, Name: fork.Spec.AssistantName}, &v1.Assistant{}); err != nil {
+		return err
+	}
+
+	msgs, err := f.messages(ctx, thread, fork.Spec.MessageName)
+	if err != nil {
+		return err
+	}
+
+	var (
+		newThread = &v1.Thread{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: name.SafeConcatName(fork.Spec.AssistantName, ""),
+				Namespace:    thread.Namespace,
+			},
+			Spec: v1.ThreadSpec{
+				AssistantName: fork.Spec.AssistantName,
+			},
+		}
+		parentName string
+	)
+
+	for i, msg := range msgs {
+		msgCopy := CopyMessage(&msg, parentName, i != len(msgs)-1)
+		if err := f.Client.Create(ctx, msgCopy); err != nil {
+			return err
+		}
+		parentName = msgCopy.Name
+
+		if i == 0 {
+			// The start message is created first, like a new thread from a client
+			newThread.Spec.StartMessageName = msgCopy.Name
+			if err := f.Client.Create(ctx, newThread); err != nil {
+				return err
+			}
+		}
+	}
+
+	fork.Status.ThreadName = newThread.Name
+	return nil
+}
+
+// messages returns the messages of the thread from its start to the message, followed by the results of the
+// tool calls of the message
+func (f *Fork) messages(ctx context.Context, thread *v1.Thread, msgName string) (result []v1.Message, _ error) {
+	if msgName == "" {
+		msgName = thread.Status.LastMessageName
+	}
+	if msgName == "" {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("thread %s has no messages to fork", thread.Name))
+	}
+
+	next := msgName
+	for next != "" {
+		var msg v1.Message
+		if err := f.Client.Get(ctx, kclient.ObjectKey{Namespace: thread.Namespace, Name: next}, &msg); err != nil {
+			return nil, err
+		}
+		if msg.Status.ThreadName != thread.Name {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("message %s is not in thread %s", msg.Name, thread.Name))
+		}
+		result = append(result, msg)
+		next = msg.Spec.ParentMessageName
+	}
+	slices.Reverse(result)
+
+	last := result[len(result)-1]
+	if last.Status.Message.IsToolCall() {
+		next = last.Status.NextMessageName
+		for next != "" {
+			var msg v1.Message
+			if err := f.Client.Get(ctx, kclient.ObjectKey{Namespace: thread.Namespace, Name: next}, &msg); err != nil {
+				return nil, err
+			}
+			if msg.Status.Message.Role != v1.RoleTypeTool {
+				break
+			}
+			result = append(result, msg)
+			next = msg.Status.NextMessageName
+		}
+	}
+
+	for _, msg := range result {
+		if msg.Status.InProgress || !msg.Status.Message.HasContent() {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("message %s is not complete", msg.Name))
+		}
+	}
+
+	return result, nil
+}
+
+// CopyMessage returns a new message with the content of msg. If more messages follow the copy, no response is
+// generated for it and its tool calls are not invoked.
+func CopyMessage(msg *v1.Message, parentName string, more bool) *v1.Message {
+	input := v1.MessageInput{
+		Content:  msg.Status.Message.Content,
+		ToolCall: msg.Status.Message.ToolCall,
+	}
+	if msg.Status.Message.Role == v1.RoleTypeAssistant {
+		input.Role = v1.RoleTypeAssistant
+	}
+
+	return &v1.Message{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "m-",
+			Namespace:    msg.Namespace,
+		},
+		Spec: v1.MessageSpec{
+			Input:             input,
+			ParentMessageName: parentName,
+			FileNames:         msg.Spec.FileNames,
+			More:              more,
+		},
+	}
+}
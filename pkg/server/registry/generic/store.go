@@ -4,15 +4,24 @@ import (
 	"github.com/acorn-io/assistant-runtime/pkg/scheme"
 	"github.com/acorn-io/mink/pkg/db"
 	"github.com/acorn-io/mink/pkg/stores"
+	"github.com/acorn-io/mink/pkg/strategy"
 	"k8s.io/apiserver/pkg/registry/rest"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func NewStore(db *db.Factory, obj kclient.Object) (rest.Storage, rest.Storage, error) {
+func NewStore(db *db.Factory, obj kclient.Object, columns ...Column) (rest.Storage, rest.Storage, error) {
 	storage, err := db.NewDBStrategy(obj)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return stores.NewComplete(scheme.Scheme, storage), stores.NewStatus(scheme.Scheme, storage), err
+	var complete strategy.CompleteStrategy = storage
+	if len(columns) > 0 {
+		complete = &tableStrategy{
+			CompleteStrategy: storage,
+			columns:          columns,
+		}
+	}
+
+	return stores.NewComplete(scheme.Scheme, complete), stores.NewStatus(scheme.Scheme, complete), err
 }
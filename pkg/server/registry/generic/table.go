@@ -0,0 +1,88 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acorn-io/mink/pkg/strategy"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Column is a printer column shown between the name and age of an object when it is listed as a table
+type Column struct {
+	Name        string
+	Description string
+	Value       func(obj kclient.Object) string
+}
+
+// tableStrategy adds printer columns to a strategy
+type tableStrategy struct {
+	strategy.CompleteStrategy
+	columns []Column
+}
+
+func (t *tableStrategy) ConvertToTable(_ context.Context, object runtime.Object, _ runtime.Object) (*metav1.Table, error) {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{
+				Name:        "Name",
+				Type:        "string",
+				Format:      "name",
+				Description: "Name of the object",
+			},
+		},
+	}
+	for _, column := range t.columns {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{
+			Name:        column.Name,
+			Type:        "string",
+			Description: column.Description,
+		})
+	}
+	table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{
+		Name:        "Age",
+		Type:        "string",
+		Description: "Time since the object was created",
+	})
+
+	if meta.IsListType(object) {
+		if list, err := meta.ListAccessor(object); err == nil {
+			table.ResourceVersion = list.GetResourceVersion()
+			table.Continue = list.GetContinue()
+			table.RemainingItemCount = list.GetRemainingItemCount()
+		}
+		return table, meta.EachListItem(object, func(item runtime.Object) error {
+			return t.appendRow(table, item)
+		})
+	}
+
+	if obj, err := meta.Accessor(object); err == nil {
+		table.ResourceVersion = obj.GetResourceVersion()
+	}
+	return table, t.appendRow(table, object)
+}
+
+func (t *tableStrategy) appendRow(table *metav1.Table, item runtime.Object) error {
+	obj, ok := item.(kclient.Object)
+	if !ok {
+		return fmt.Errorf("can not convert %T to a table row", item)
+	}
+
+	cells := []any{obj.GetName()}
+	for _, column := range t.columns {
+		cells = append(cells, column.Value(obj))
+	}
+	cells = append(cells, duration.HumanDuration(metav1.Now().Sub(obj.GetCreationTimestamp().Time)))
+
+	table.Rows = append(table.Rows, metav1.TableRow{
+		Cells: cells,
+		Object: runtime.RawExtension{
+			Object: item,
+		},
+	})
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/acorn-io/assistant-runtime/pkg/openapi/generated"
 	"github.com/acorn-io/assistant-runtime/pkg/scheme"
+	"github.com/acorn-io/assistant-runtime/pkg/server/openaiapi"
 	"github.com/acorn-io/assistant-runtime/pkg/server/registry/apigroups/assistant"
 	"github.com/acorn-io/assistant-runtime/pkg/server/services"
 	"github.com/acorn-io/assistant-runtime/pkg/version"
@@ -39,19 +40,26 @@ func startMinkServer(ctx context.Context, cfg services.Config, services *service
 	minkConfig := &mserver.Config{
 		Name:              "Assistant Runtime",
 		Version:           version.Get().String(),
-		Authenticator:     services.Authn,
+		Authenticator:     services.ServerAuthn(),
 		HTTPListenPort:    cfg.HTTPListenPort,
 		HTTPSListenPort:   cfg.HTTPSListenPort,
 		OpenAPIConfig:     generated.GetOpenAPIDefinitions,
 		Scheme:            scheme.Scheme,
 		APIGroups:         apiGroups,
 		ReadinessCheckers: []healthz.HealthChecker{services.DB},
+		// Streams of messages stay open while the messages are generated
+		LongRunningResources: []string{"stream"},
 	}
 
 	if cfg.AuditLogPolicyFile != "" && cfg.AuditLogPath != "" {
 		minkConfig.AuditConfig = mserver.NewAuditOptions(cfg.AuditLogPolicyFile, cfg.AuditLogPath)
 	}
 
+	openaiHandler, err := openaiapi.NewHandler(services.Client, services.RESTConfig, cfg.OpenAINamespace, services.Authn)
+	if err != nil {
+		return err
+	}
+
 	brentHandler, brentStartHook, err := brent.Handler(ctx, &brent.Config{
 		RESTConfig: services.RESTConfig,
 		MinkConfig: minkConfig,
@@ -68,6 +76,8 @@ func startMinkServer(ctx context.Context, cfg services.Config, services *service
 						GitVersion: version.Get().String(),
 						GitCommit:  version.Get().Commit,
 					})
+				} else if strings.HasPrefix(req.URL.Path, openaiapi.Prefix) || req.URL.Path == openaiapi.ChatCompletionsPath {
+					openaiHandler.ServeHTTP(rw, req)
 				} else if strings.HasPrefix(req.URL.Path, "/v1") {
 					brentHandler.ServeHTTP(rw, req)
 				} else {
@@ -1,8 +1,14 @@
 package services
 
 import (
+	"context"
+	"crypto/subtle"
 	_ "embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"strings"
 
 	"github.com/acorn-io/assistant-runtime/pkg/scheme"
 	"github.com/acorn-io/baaah/pkg/randomtoken"
@@ -10,6 +16,9 @@ import (
 	"github.com/acorn-io/baaah/pkg/restconfig"
 	"github.com/acorn-io/mink/pkg/db"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	"k8s.io/apiserver/pkg/authentication/request/union"
+	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/client-go/rest"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -17,15 +26,17 @@ import (
 type Config struct {
 	HTTPListenPort     int    `usage:"HTTP port to listen on" default:"8080"`
 	HTTPSListenPort    int    `usage:"HTTPS port to listen on"`
-	AdminToken         string `usage:"Token for admin access, will be generated if not passed"`
+	AdminToken         string `usage:"Token for admin access, such as the controller publishing the content of messages, will be read from the admin token file if not passed"`
+	AdminTokenFile     string `usage:"File the admin token is read from if it is not passed, it is generated and written to the file if the file doesn't exist. The controller reads the same file." default:"admin-token"`
 	AuditLogPath       string `usage:"Location of where to store audit logs"`
 	AuditLogPolicyFile string `usage:"Location of audit log policy file"`
 	DSN                string `usage:"Database dsn in driver://connection_string format" default:"sqlite://file:assistant.db?_journal=WAL&cache=shared&_busy_timeout=30000"`
+	OpenAINamespace    string `name:"openai-namespace" usage:"Namespace of the assistants and threads served by the OpenAI compatible API" default:"acorn"`
 }
 
 func New(config Config) (_ *Services, err error) {
 	if config.AdminToken == "" {
-		config.AdminToken, err = randomtoken.Generate()
+		config.AdminToken, err = adminToken(config.AdminTokenFile)
 		if err != nil {
 			return nil, err
 		}
@@ -52,14 +63,65 @@ func New(config Config) (_ *Services, err error) {
 		Client:     downstreamClient,
 		RESTConfig: downstreamConfig,
 		DB:         dbClient,
+		AdminToken: config.AdminToken,
 	}
 
 	return services, nil
 }
 
+// adminToken reads the admin token from file, or generates one and writes it to file so that the controller can
+// read it
+func adminToken(file string) (string, error) {
+	if file == "" {
+		return "", fmt.Errorf("either the admin token or the admin token file is required")
+	}
+
+	data, err := os.ReadFile(file)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("reading admin token: %w", err)
+	}
+
+	token, err := randomtoken.Generate()
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(file, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("writing admin token: %w", err)
+	}
+	return token, nil
+}
+
 type Services struct {
 	Client     kclient.Client
 	RESTConfig *rest.Config
 	DB         *db.Factory
 	Authn      authenticator.Request
+	AdminToken string
+}
+
+// AdminUser is the user of requests with the admin token, such as the controller publishing the content of messages
+const AdminUser = "assistant-runtime:admin"
+
+// ServerAuthn authenticates the requests to the API server. Requests with the admin token are from AdminUser,
+// others are authenticated by Authn.
+func (s *Services) ServerAuthn() authenticator.Request {
+	admin := bearertoken.New(authenticator.TokenFunc(func(_ context.Context, token string) (*authenticator.Response, bool, error) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.AdminToken)) != 1 {
+			return nil, false, nil
+		}
+		return &authenticator.Response{
+			User: &user.DefaultInfo{
+				Name:   AdminUser,
+				Groups: []string{user.SystemPrivilegedGroup},
+			},
+		}, true, nil
+	}))
+	if s.Authn == nil {
+		return admin
+	}
+	return union.New(admin, s.Authn)
 }
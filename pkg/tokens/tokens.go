@@ -0,0 +1,132 @@
+package tokens
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
+)
+
+const (
+	DefaultContextWindow = 8192
+
+	// messageOverhead is the number of tokens used by the role and separators of each message
+	messageOverhead = 4
+	// imageTokens is the cost of a high detail image. Low detail images are 85 tokens.
+	imageTokens    = 765
+	lowImageTokens = 85
+)
+
+type window struct {
+	prefix string
+	tokens int
+}
+
+// windows are matched by the longest prefix of the model
+var windows = []window{
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4-1106", 128000},
+	{"gpt-4-0125", 128000},
+	{"gpt-4-vision", 128000},
+	{"gpt-4-32k", 32768},
+	{"gpt-4", 8192},
+	{"gpt-3.5-turbo-instruct", 4096},
+	{"gpt-3.5-turbo", 16385},
+	{"claude-3", 200000},
+	{"claude-2.1", 200000},
+	{"claude-2", 100000},
+	{"claude-instant", 100000},
+	{"mixtral", 32768},
+	{"mistral", 32768},
+	{"llama2", 4096},
+	{"llava", 4096},
+	{"codellama", 16384},
+}
+
+// ContextWindow returns the maximum number of tokens the model accepts for the prompt and response combined.
+// Models may be prefixed with a provider name, as in "anthropic/claude-3-opus-20240229".
+func ContextWindow(model string) int {
+	if _, rest, ok := strings.Cut(model, "/"); ok {
+		model = rest
+	}
+	var (
+		result      = DefaultContextWindow
+		matchLength int
+	)
+	for _, w := range windows {
+		if strings.HasPrefix(model, w.prefix) && len(w.prefix) > matchLength {
+			result, matchLength = w.tokens, len(w.prefix)
+		}
+	}
+	return result
+}
+
+// charsPerToken is the average number of characters in a token. Models with a smaller vocabulary, such as
+// llama, use more tokens for the same text.
+func charsPerToken(model string) float64 {
+	if _, rest, ok := strings.Cut(model, "/"); ok {
+		model = rest
+	}
+	switch {
+	case strings.HasPrefix(model, "gpt-"):
+		return 4
+	case strings.HasPrefix(model, "claude-"):
+		return 3.5
+	default:
+		return 3
+	}
+}
+
+// Text estimates the number of tokens in text. The estimate errs on the high side so that prompts that
+// are sized to fit a context window do fit.
+func Text(model, text string) int {
+	if text == "" {
+		return 0
+	}
+	return int(float64(utf8.RuneCountInString(text))/charsPerToken(model)) + 1
+}
+
+// Message estimates the number of tokens msg uses in a prompt.
+func Message(model string, msg v1.MessageBody) int {
+	count := messageOverhead
+	if msg.ToolCall != nil {
+		count += Text(model, msg.ToolCall.ID)
+	}
+	for _, content := range msg.Content {
+		count += Text(model, content.Text)
+		if content.ToolCall != nil {
+			count += messageOverhead + Text(model, content.ToolCall.ID) + Text(model, content.ToolCall.Function.Name) +
+				Text(model, content.ToolCall.Function.Arguments)
+		}
+		if content.Image != nil {
+			if content.Image.Detail == v1.ImageURLDetailLow {
+				count += lowImageTokens
+			} else {
+				count += imageTokens
+			}
+		}
+	}
+	return count
+}
+
+// Messages estimates the number of tokens msgs use in a prompt.
+func Messages(model string, msgs []v1.MessageBody) (count int) {
+	for _, msg := range msgs {
+		count += Message(model, msg)
+	}
+	return
+}
+
+// Tools estimates the number of tokens the definitions of tools use in a prompt.
+func Tools(model string, tools []v1.Tool) (count int) {
+	for _, tool := range tools {
+		data, err := json.Marshal(tool.Function)
+		if err != nil {
+			continue
+		}
+		count += Text(model, string(data))
+	}
+	return
+}
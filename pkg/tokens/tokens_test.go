@@ -0,0 +1,26 @@
+package tokens
+
+import "testing"
+
+func TestContextWindow(t *testing.T) {
+	for model, want := range map[string]int{
+		"gpt-4o":                           128000,
+		"gpt-4o-2024-05-13":                128000,
+		"gpt-4o-mini":                      128000,
+		"gpt-4-turbo":                      128000,
+		"gpt-4-turbo-2024-04-09":           128000,
+		"gpt-4-1106-preview":               128000,
+		"gpt-4-32k-0613":                   32768,
+		"gpt-4":                            8192,
+		"gpt-4-0613":                       8192,
+		"gpt-3.5-turbo-instruct":           4096,
+		"gpt-3.5-turbo":                    16385,
+		"openai/gpt-4o":                    128000,
+		"anthropic/claude-3-opus-20240229": 200000,
+		"unknown-model":                    DefaultContextWindow,
+	} {
+		if got := ContextWindow(model); got != want {
+			t.Errorf("ContextWindow(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	v1 "github.com/acorn-io/assistant-runtime/pkg/apis/assistant.acorn.io/v1"
@@ -19,6 +20,9 @@ import (
 
 var (
 	urlBase = os.Getenv("IMAGE_URL_BASE")
+	// storedImagePath matches the URL of an image stored by StoreImage. The URL is relative if it was stored by a
+	// process without IMAGE_URL_BASE, such as the CLI.
+	storedImagePath = regexp.MustCompile(`^/apis/assistant\.acorn\.io/v1/namespaces/([^/]+)/images/([^/]+)/serve$`)
 )
 
 func ToVisionMessage(ctx context.Context, c kclient.Client, namespace string, message v1.MessageBody) (v1.MessageBody, error) {
@@ -66,15 +70,8 @@ func ToVisionMessage(ctx context.Context, c kclient.Client, namespace string, me
 }
 
 func Base64FromStored(ctx context.Context, c kclient.Client, namespace string, url string) (string, string, error) {
-	if !strings.HasPrefix(url, urlBase) {
-		return "", "", nil
-	}
-	parts := strings.Split(url, "/")
-	if len(parts) < 3 {
-		return "", "", nil
-	}
-	urlNamespace, name := parts[len(parts)-4], parts[len(parts)-2]
-	if urlNamespace != namespace {
+	urlNamespace, name, ok := storedImage(url)
+	if !ok || urlNamespace != namespace {
 		return "", "", nil
 	}
 
@@ -87,15 +84,50 @@ func Base64FromStored(ctx context.Context, c kclient.Client, namespace string, u
 	return base64.StdEncoding.EncodeToString(image.Spec.Content), image.Spec.ContentType, nil
 }
 
+// storedImage returns the namespace and name of the image if url is the URL of an image stored by StoreImage
+func storedImage(url string) (string, string, bool) {
+	path, ok := url, strings.HasPrefix(url, "/")
+	if !ok && urlBase != "" {
+		path, ok = strings.CutPrefix(url, urlBase)
+	}
+	if !ok {
+		return "", "", false
+	}
+	match := storedImagePath.FindStringSubmatch(path)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// ImageToURL returns the URL sent to the model for the image. Models with vision get the content of the image,
+// including the images stored by the runtime, others get a URL to it.
 func ImageToURL(ctx context.Context, k8s kclient.Client, namespace string, vision bool, message v1.ChatMessageImageURL) (string, error) {
 	if message.URL != "" {
-		return message.URL, nil
+		if !vision {
+			if strings.HasPrefix(message.URL, "/") {
+				// Stored by a process that doesn't know the URL of the API
+				return urlBase + message.URL, nil
+			}
+			return message.URL, nil
+		}
+		b64, contentType, err := Base64FromStored(ctx, k8s, namespace, message.URL)
+		if err != nil || b64 == "" {
+			return message.URL, err
+		}
+		return fmt.Sprintf("data:%s;base64,%s", contentType, b64), nil
 	}
 
 	if vision {
 		return fmt.Sprintf("data:%s;base64,%s", message.ContentType, message.Base64), nil
 	}
 
+	return StoreImage(ctx, k8s, namespace, message)
+}
+
+// StoreImage stores the base64 content of the image as an Image named by its content, and returns the URL it is
+// served from
+func StoreImage(ctx context.Context, k8s kclient.Client, namespace string, message v1.ChatMessageImageURL) (string, error) {
 	data, err := base64.StdEncoding.DecodeString(message.Base64)
 	if err != nil {
 		return "", err
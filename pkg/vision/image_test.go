@@ -0,0 +1,25 @@
+package vision
+
+import "testing"
+
+func TestStoredImage(t *testing.T) {
+	defer func(base string) { urlBase = base }(urlBase)
+	urlBase = "https://api.example.com"
+
+	for url, want := range map[string]string{
+		"/apis/assistant.acorn.io/v1/namespaces/acorn/images/iabc/serve":                          "acorn/iabc",
+		"https://api.example.com/apis/assistant.acorn.io/v1/namespaces/acorn/images/iabc/serve":   "acorn/iabc",
+		"https://other.example.com/apis/assistant.acorn.io/v1/namespaces/acorn/images/iabc/serve": "",
+		"/apis/assistant.acorn.io/v1/namespaces/acorn/images/iabc":                                "",
+		"https://example.com/cat.png": "",
+	} {
+		namespace, name, ok := storedImage(url)
+		got := ""
+		if ok {
+			got = namespace + "/" + name
+		}
+		if got != want {
+			t.Errorf("storedImage(%q) = %q, want %q", url, got, want)
+		}
+	}
+}